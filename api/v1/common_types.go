@@ -27,6 +27,7 @@ var (
 	ForceAnnotation                  = fmt.Sprintf("%s/force", GroupVersion.Group)
 	RevisionAnnotation               = fmt.Sprintf("%s/revision", GroupVersion.Group)
 	CopyFromAnnotation               = fmt.Sprintf("%s/copyFrom", GroupVersion.Group)
+	ApproveAnnotation                = fmt.Sprintf("%s/approve", GroupVersion.Group)
 )
 
 // InputProvider is the interface that the ResourceSet
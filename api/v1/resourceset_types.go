@@ -17,6 +17,20 @@ import (
 
 const (
 	ResourceSetKind = "ResourceSet"
+
+	// InvalidInputsReason is used when the inputs don't match the inputs schema.
+	InvalidInputsReason = "InvalidInputs"
+
+	// InputStrategyFlatten concatenates the inputs from all providers.
+	InputStrategyFlatten = "Flatten"
+
+	// InputStrategyPermute combines the inputs from all providers
+	// into their Cartesian product.
+	InputStrategyPermute = "Permute"
+
+	// AccessDeniedReason is used when the input providers are referenced
+	// from another namespace and cross-namespace references are disabled.
+	AccessDeniedReason = "AccessDenied"
 )
 
 // ResourceSetSpec defines the desired state of ResourceSet
@@ -37,6 +51,20 @@ type ResourceSetSpec struct {
 	// +optional
 	InputsFrom []InputProviderReference `json:"inputsFrom,omitempty"`
 
+	// InputStrategy defines how the inputs from the in-line inputs
+	// and the input providers are combined.
+	// When not set, the inputs are flattened into a single list.
+	// +optional
+	InputStrategy *InputStrategySpec `json:"inputStrategy,omitempty"`
+
+	// InputsSchema is an OpenAPI v3 schema used to validate each input set,
+	// from both the in-line inputs and the input providers, before the
+	// resources are generated. The default values defined in the schema
+	// are set for the fields missing from the inputs.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	InputsSchema *apiextensionsv1.JSON `json:"inputsSchema,omitempty"`
+
 	// Resources contains the list of Kubernetes resources to reconcile.
 	// +optional
 	Resources []*apiextensionsv1.JSON `json:"resources,omitempty"`
@@ -65,6 +93,10 @@ type ResourceSetSpec struct {
 	Wait bool `json:"wait,omitempty"`
 }
 
+// InputProviderReference defines a reference to one input provider by name,
+// or to multiple input providers by label selector.
+// +kubebuilder:validation:XValidation:rule="has(self.name) != has(self.selector)",message="exactly one of name or selector must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.key) || has(self.name)",message="key can only be set together with name"
 type InputProviderReference struct {
 	// APIVersion of the input provider resource.
 	// When not set, the APIVersion of the ResourceSet is used.
@@ -77,6 +109,38 @@ type InputProviderReference struct {
 	Kind string `json:"kind"`
 
 	// Name of the input provider resource.
+	// Cannot be set together with Selector.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the input provider resources.
+	// When not set, the namespace of the ResourceSet is used.
+	// Referencing providers from other namespaces can be
+	// disabled with the '--no-cross-namespace-refs' operator flag.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Selector is a label selector that matches the input provider
+	// resources in the namespace. Cannot be set together with Name.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Key under which the input sets of the provider are nested
+	// with the 'Permute' input strategy. When not set, the key
+	// is computed from the provider name. Can only be set with Name.
+	// +kubebuilder:validation:Pattern="^[a-z0-9_]+$"
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// InputStrategySpec defines how the inputs are combined.
+type InputStrategySpec struct {
+	// Name of the input strategy, supported values are:
+	// 'Flatten' concatenates the input sets from all providers into a single list;
+	// 'Permute' generates the Cartesian product of the input sets from all providers,
+	// with the input sets of each provider nested under the provider name.
+	// +kubebuilder:validation:Enum=Flatten;Permute
 	// +required
 	Name string `json:"name"`
 }
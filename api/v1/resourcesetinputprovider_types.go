@@ -15,31 +15,127 @@ import (
 )
 
 const (
-	ResourceSetInputProviderKind    = "ResourceSetInputProvider"
-	InputProviderGitHubBranch       = "GitHubBranch"
-	InputProviderGitHubPullRequest  = "GitHubPullRequest"
-	InputProviderGitLabBranch       = "GitLabBranch"
-	InputProviderGitLabMergeRequest = "GitLabMergeRequest"
+	ResourceSetInputProviderKind            = "ResourceSetInputProvider"
+	InputProviderGitHubBranch               = "GitHubBranch"
+	InputProviderGitHubPullRequest          = "GitHubPullRequest"
+	InputProviderGitHubTag                  = "GitHubTag"
+	InputProviderGitLabBranch               = "GitLabBranch"
+	InputProviderGitLabMergeRequest         = "GitLabMergeRequest"
+	InputProviderGitLabTag                  = "GitLabTag"
+	InputProviderGiteaBranch                = "GiteaBranch"
+	InputProviderGiteaPullRequest           = "GiteaPullRequest"
+	InputProviderBitbucketServerBranch      = "BitbucketServerBranch"
+	InputProviderBitbucketServerPullRequest = "BitbucketServerPullRequest"
+	InputProviderAzureDevOpsBranch          = "AzureDevOpsBranch"
+	InputProviderAzureDevOpsPullRequest     = "AzureDevOpsPullRequest"
+	InputProviderOCIArtifactTag             = "OCIArtifactTag"
+	InputProviderKubernetesSelector         = "KubernetesSelector"
+	InputProviderHTTPJSON                   = "HTTPJSON"
+	InputProviderGitHubOrganization         = "GitHubOrganization"
+	InputProviderGitLabGroup                = "GitLabGroup"
+	InputProviderGitHubFile                 = "GitHubFile"
+	InputProviderGitLabFile                 = "GitLabFile"
+	InputProviderFluxArtifact               = "FluxArtifact"
+
+	// RateLimitedCondition reports the API rate limit status of the Git provider.
+	RateLimitedCondition     = "RateLimited"
+	RateLimitExceededReason  = "RateLimitExceeded"
+	RateLimitAvailableReason = "RateLimitAvailable"
+
+	// FrozenCondition reports that the exported inputs
+	// are retained outside the schedule windows.
+	FrozenCondition       = "Frozen"
+	OutsideScheduleReason = "OutsideSchedule"
+	InvalidScheduleReason = "InvalidSchedule"
+
+	// InputsChangedReason is used when the exported inputs
+	// have input sets added, updated or removed.
+	InputsChangedReason = "InputsChanged"
+
+	// InputsExpiringReason is used when input sets are about to expire,
+	// and InputsExpiredReason when expired input sets are dropped.
+	InputsExpiringReason = "InputsExpiring"
+	InputsExpiredReason  = "InputsExpired"
+
+	// InputsPendingApprovalReason is used when input sets are held
+	// until approved, and InputsApprovedReason when they are approved.
+	InputsPendingApprovalReason = "InputsPendingApproval"
+	InputsApprovedReason        = "InputsApproved"
+
+	// ApprovalMethodLabel, ApprovalMethodReview and ApprovalMethodAnnotation
+	// are the methods by which the input sets can be approved.
+	ApprovalMethodLabel      = "Label"
+	ApprovalMethodReview     = "Review"
+	ApprovalMethodAnnotation = "Annotation"
 )
 
 // ResourceSetInputProviderSpec defines the desired state of ResourceSetInputProvider
+// +kubebuilder:validation:XValidation:rule="self.type in ['KubernetesSelector', 'FluxArtifact'] || has(self.url) || has(self.urls)",message="spec.url is required for this type"
+// +kubebuilder:validation:XValidation:rule="!(has(self.url) && has(self.urls))",message="spec.url and spec.urls are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.urls) || self.type.endsWith('Branch') || self.type.endsWith('Request') || self.type.endsWith('Tag')",message="spec.urls is only supported for the branch, pull/merge request and tag types"
+// +kubebuilder:validation:XValidation:rule="!has(self.urls) || (!has(self.approval) && !(has(self.reportStatus) && self.reportStatus))",message="spec.approval and spec.reportStatus are not supported with spec.urls"
+// +kubebuilder:validation:XValidation:rule="!(self.type in ['GitHubFile', 'GitLabFile', 'FluxArtifact']) || has(self.file)",message="spec.file is required for this type"
+// +kubebuilder:validation:XValidation:rule="self.type != 'FluxArtifact' || has(self.sourceRef)",message="spec.sourceRef is required for the FluxArtifact type"
+// +kubebuilder:validation:XValidation:rule="self.type != 'KubernetesSelector' || has(self.selector)",message="spec.selector is required for the KubernetesSelector type"
+// +kubebuilder:validation:XValidation:rule="!has(self.approval) || self.type in ['GitHubPullRequest', 'GitLabMergeRequest']",message="spec.approval is only supported for the GitHubPullRequest and GitLabMergeRequest types"
+// +kubebuilder:validation:XValidation:rule="self.type != 'GitLabMergeRequest' || !has(self.approval) || !(has(self.approval.review) && self.approval.review && has(self.approval.reapproveOnNewCommit) && self.approval.reapproveOnNewCommit)",message="spec.approval.review is not supported with reapproveOnNewCommit for the GitLabMergeRequest type"
+// +kubebuilder:validation:XValidation:rule="!has(self.reportStatus) || !self.reportStatus || self.type in ['GitHubPullRequest', 'GitLabMergeRequest']",message="spec.reportStatus is only supported for the GitHubPullRequest and GitLabMergeRequest types"
+// +kubebuilder:validation:XValidation:rule="!has(self.exportCommitTimestamp) || !self.exportCommitTimestamp || self.type in ['GitHubPullRequest', 'GitLabMergeRequest']",message="spec.exportCommitTimestamp is only supported for the GitHubPullRequest and GitLabMergeRequest types"
 type ResourceSetInputProviderSpec struct {
 	// Type specifies the type of the input provider.
-	// +kubebuilder:validation:Enum=GitHubBranch;GitHubPullRequest;GitLabBranch;GitLabMergeRequest
+	// +kubebuilder:validation:Enum=GitHubBranch;GitHubPullRequest;GitHubTag;GitLabBranch;GitLabMergeRequest;GitLabTag;GiteaBranch;GiteaPullRequest;BitbucketServerBranch;BitbucketServerPullRequest;AzureDevOpsBranch;AzureDevOpsPullRequest;OCIArtifactTag;KubernetesSelector;HTTPJSON;GitHubOrganization;GitLabGroup;GitHubFile;GitLabFile;FluxArtifact
 	// +required
 	Type string `json:"type"`
 
 	// URL specifies the HTTP/S address of the input provider API.
 	// When connecting to a Git provider, the URL should point to the repository address.
-	// +kubebuilder:validation:Pattern="^(http|https)://.*$"
-	// +required
-	URL string `json:"url"`
+	// For the GitHubOrganization and GitLabGroup types, the URL should point
+	// to the organization or group address.
+	// When connecting to an OCI registry, the URL should point to the repository
+	// address prefixed with 'oci://'.
+	// The URL, or the URLs, is required for all types except KubernetesSelector and FluxArtifact.
+	// +kubebuilder:validation:Pattern="^(http|https|oci)://.*$"
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// URLs specifies the addresses of multiple repositories of the same
+	// provider type, which are queried concurrently with the same credentials.
+	// The results are merged, and the 'id' of each input set is computed from
+	// the repository path and the result id. Mutually exclusive with URL.
+	// +listType=set
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:Pattern="^(http|https|oci)://.*$"
+	// +optional
+	URLs []string `json:"urls,omitempty"`
+
+	// Selector specifies the Kubernetes objects to export inputs from.
+	// The selector is required for the KubernetesSelector type.
+	// +optional
+	Selector *ResourceSetInputSelector `json:"selector,omitempty"`
+
+	// JSON specifies how to extract the inputs from the JSON
+	// response of the HTTPJSON type.
+	// +optional
+	JSON *ResourceSetInputJSON `json:"json,omitempty"`
+
+	// File specifies the YAML or JSON file containing the list of inputs.
+	// The file is required for the GitHubFile, GitLabFile and FluxArtifact types.
+	// +optional
+	File *ResourceSetInputFile `json:"file,omitempty"`
+
+	// SourceRef specifies the Flux source from which the artifact is downloaded.
+	// The source reference is required for the FluxArtifact type.
+	// +optional
+	SourceRef *ResourceSetInputSourceReference `json:"sourceRef,omitempty"`
 
 	// SecretRef specifies the Kubernetes Secret containing the basic-auth credentials
 	// to access the input provider. The secret must contain the keys
 	// 'username' and 'password'.
 	// When connecting to a Git provider, the password should be a personal access token
 	// that grants read-only access to the repository.
+	// When connecting to an OCI registry, the secret must be of type
+	// 'kubernetes.io/dockerconfigjson' and contain the registry credentials.
 	// +optional
 	SecretRef *meta.LocalObjectReference `json:"secretRef,omitempty"`
 
@@ -66,9 +162,134 @@ type ResourceSetInputProviderSpec struct {
 	// Skip defines whether we need to skip input provider response updates.
 	// +optional
 	Skip *ResourceSetInputSkip `json:"skip,omitempty"`
+
+	// Transform maps input names to CEL expressions that compute additional
+	// inputs from each exported input set, e.g. 'shortSha: "sha.substring(0, 7)"'.
+	// The exported inputs are available as variables and the expressions
+	// must return a string. The 'id' input can't be transformed.
+	// +kubebuilder:validation:XValidation:rule="!('id' in self)",message="the id input can't be transformed"
+	// +optional
+	Transform map[string]string `json:"transform,omitempty"`
+
+	// ReportStatus enables reporting the readiness of the ResourceSets
+	// that use the exported inputs as commit statuses on the pull/merge
+	// requests head commit. The credentials from SecretRef must grant
+	// write access to the commit statuses.
+	// Supported only for the GitHubPullRequest and GitLabMergeRequest types.
+	// +optional
+	ReportStatus bool `json:"reportStatus,omitempty"`
+
+	// ExportCommitTimestamp enables exporting the committer date of the
+	// pull/merge requests head commit as the 'commitTimestamp' input.
+	// Looking up the commit requires an extra API call for each pull/merge
+	// request, the lookup is always enabled when Expiry.MaxAge is set.
+	// Supported only for the GitHubPullRequest and GitLabMergeRequest types.
+	// +optional
+	ExportCommitTimestamp bool `json:"exportCommitTimestamp,omitempty"`
+
+	// Schedule defines the time windows in which the exported inputs are
+	// allowed to change. Outside the windows, the provider keeps fetching
+	// the inputs, but the last exported inputs are retained and the changes
+	// are exported as soon as one of the windows opens.
+	// +optional
+	Schedule []ResourceSetInputSchedule `json:"schedule,omitempty"`
+
+	// Expiry defines when the input sets are dropped from the exported inputs,
+	// based on the age of their head commit or on their inactivity.
+	// +optional
+	Expiry *ResourceSetInputExpiry `json:"expiry,omitempty"`
+
+	// Approval holds the newly discovered pull/merge requests in the pending
+	// inputs until they are approved by a maintainer. The input sets can be
+	// approved with a label or review from one of the approvers, or with the
+	// 'fluxcd.controlplane.io/approve' annotation listing the input set ids.
+	// Supported only for the GitHubPullRequest and GitLabMergeRequest types.
+	// +optional
+	Approval *ResourceSetInputApproval `json:"approval,omitempty"`
+}
+
+// ResourceSetInputSelector defines the Kubernetes objects to export inputs from.
+type ResourceSetInputSelector struct {
+	// APIVersion of the selected objects, e.g. 'v1' or 'apps/v1'.
+	// +required
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the selected objects, e.g. 'Namespace'.
+	// Namespaced objects are selected from the
+	// ResourceSetInputProvider namespace.
+	// +required
+	Kind string `json:"kind"`
+
+	// LabelSelector filters the selected objects by labels.
+	// When not set, all the objects of the given kind are selected.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// Fields maps input names to JSONPath expressions evaluated against each
+	// selected object, e.g. 'tenant: "{.metadata.labels.tenant}"'.
+	// When a field is not found in the object, the input is not exported.
+	// +optional
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// ResourceSetInputFile defines the file containing the list of inputs.
+type ResourceSetInputFile struct {
+	// Path of the file relative to the root of the
+	// repository or artifact, e.g. 'environments.yaml'.
+	// +required
+	Path string `json:"path"`
+
+	// Ref specifies the Git branch, tag or commit SHA to read the file from,
+	// for the GitHubFile and GitLabFile types.
+	// When not set, the default branch of the repository is used.
+	// +optional
+	Ref string `json:"ref,omitempty"`
+}
+
+// ResourceSetInputSourceReference defines a reference to a Flux source.
+type ResourceSetInputSourceReference struct {
+	// Kind of the Flux source.
+	// +kubebuilder:validation:Enum=GitRepository;OCIRepository
+	// +required
+	Kind string `json:"kind"`
+
+	// Name of the Flux source, the source must be in
+	// the same namespace as the ResourceSetInputProvider.
+	// +required
+	Name string `json:"name"`
+}
+
+// ResourceSetInputJSON defines how to extract the inputs from a JSON document.
+// +kubebuilder:validation:XValidation:rule="!(has(self.items) && has(self.itemsExpr))",message="items and itemsExpr are mutually exclusive"
+type ResourceSetInputJSON struct {
+	// Items is a JSONPath expression that selects the list of items
+	// from the JSON document, e.g. '{.tenants[*]}'. When neither items
+	// nor itemsExpr are set, the JSON document must be an array.
+	// +optional
+	Items string `json:"items,omitempty"`
+
+	// ItemsExpr is a CEL expression that returns the list of items
+	// from the JSON document available as the 'response' variable,
+	// e.g. 'response.tenants.filter(t, t.enabled)'.
+	// +optional
+	ItemsExpr string `json:"itemsExpr,omitempty"`
+
+	// ID is a JSONPath expression that selects the unique identifier of
+	// each item, e.g. '{.name}'. The exported 'id' input is the Adler-32
+	// checksum of the identifier. When not set, the checksum is computed
+	// from the whole item, and the 'id' changes when the item changes.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Fields maps input names to JSONPath expressions evaluated against
+	// each item, e.g. 'tenant: "{.name}"'. When not set, all the
+	// fields of the item are exported.
+	// +optional
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 // ResourceSetInputFilter defines the filter to apply to the input provider response.
+// +kubebuilder:validation:XValidation:rule="!has(self.sortOrder) || has(self.sort)",message="sortOrder requires sort to be set"
 type ResourceSetInputFilter struct {
 	// IncludeBranch specifies the regular expression to filter the branches
 	// that the input provider should include.
@@ -80,10 +301,114 @@ type ResourceSetInputFilter struct {
 	// +optional
 	ExcludeBranch string `json:"excludeBranch,omitempty"`
 
+	// IncludeTag specifies the regular expression to filter the tags
+	// that the input provider should include.
+	// +optional
+	IncludeTag string `json:"includeTag,omitempty"`
+
+	// ExcludeTag specifies the regular expression to filter the tags
+	// that the input provider should exclude.
+	// +optional
+	ExcludeTag string `json:"excludeTag,omitempty"`
+
 	// Labels specifies the list of labels to filter the input provider response.
 	// +optional
 	Labels []string `json:"labels,omitempty"`
 
+	// TitleLabelPrefix specifies the prefix that marks a label in the pull
+	// request title, e.g. with '#' the title 'Fix login #preview' has the
+	// label 'preview'. When not set, the bracketed tags at the start of the
+	// title are used as labels, e.g. '[preview] Fix login'.
+	// Supported only for the BitbucketServerPullRequest type.
+	// +optional
+	TitleLabelPrefix string `json:"titleLabelPrefix,omitempty"`
+
+	// IncludeBaseBranch specifies the regular expression to filter the pull
+	// requests by the target (base) branch that the changes are merged into.
+	// +optional
+	IncludeBaseBranch string `json:"includeBaseBranch,omitempty"`
+
+	// ExcludeDraft specifies whether the draft pull requests should be excluded.
+	// +optional
+	ExcludeDraft bool `json:"excludeDraft,omitempty"`
+
+	// ExcludeForks specifies whether the pull requests opened
+	// from forked repositories should be excluded.
+	// +optional
+	ExcludeForks bool `json:"excludeForks,omitempty"`
+
+	// IncludeAuthors specifies the list of usernames allowed to open
+	// the pull requests. When set, the pull requests opened by
+	// other users are excluded.
+	// +optional
+	IncludeAuthors []string `json:"includeAuthors,omitempty"`
+
+	// ExcludeAuthors specifies the list of usernames whose
+	// pull requests should be excluded.
+	// +optional
+	ExcludeAuthors []string `json:"excludeAuthors,omitempty"`
+
+	// MaxAge specifies the maximum age of the pull requests
+	// computed from their creation time, e.g. '168h'.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// Topics specifies the list of topics that the repositories must have
+	// to be included. Supported only for the GitHubOrganization and GitLabGroup types.
+	// +optional
+	Topics []string `json:"topics,omitempty"`
+
+	// Visibility specifies the visibility of the repositories to include.
+	// Supported only for the GitHubOrganization and GitLabGroup types.
+	// +kubebuilder:validation:Enum=public;private;internal
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+
+	// IncludeArchived specifies whether the archived repositories should be included.
+	// Supported only for the GitHubOrganization and GitLabGroup types.
+	// +optional
+	IncludeArchived bool `json:"includeArchived,omitempty"`
+
+	// IncludeSubgroups specifies whether the projects of the subgroups
+	// should be included. Supported only for the GitLabGroup type.
+	// +optional
+	IncludeSubgroups bool `json:"includeSubgroups,omitempty"`
+
+	// Semver specifies the semantic version range to filter the tags
+	// that the input provider should include. When set, tags that are
+	// not valid semantic versions are excluded.
+	// +optional
+	Semver string `json:"semver,omitempty"`
+
+	// LatestPerMinor specifies the number of versions to include for
+	// each minor release line, e.g. when set to 1 only the latest patch
+	// version of each minor release is included.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LatestPerMinor int `json:"latestPerMinor,omitempty"`
+
+	// Expr specifies a CEL expression evaluated for each result returned
+	// by the Git provider, only the results for which the expression
+	// returns true are exported. The result fields are available as
+	// variables, e.g. "title.startsWith('[preview]') && size(labels) < 3".
+	// +optional
+	Expr string `json:"expr,omitempty"`
+
+	// Sort specifies the attribute used to order the results before
+	// the limit is applied. The results that don't have the attribute
+	// are placed last, and the ties are ordered by id.
+	// When not set, the results are returned in the Git provider order.
+	// +kubebuilder:validation:Enum=created;updated;name;semver
+	// +optional
+	Sort string `json:"sort,omitempty"`
+
+	// SortOrder specifies the direction of the sort, defaults to 'asc'.
+	// +kubebuilder:validation:Enum=asc;desc
+	// +optional
+	SortOrder string `json:"sortOrder,omitempty"`
+
 	// Limit specifies the maximum number of input sets to return.
 	// When not set, the default limit is 100.
 	// +optional
@@ -98,6 +423,81 @@ type ResourceSetInputSkip struct {
 	Labels []string `json:"labels,omitempty"`
 }
 
+// ResourceSetInputSchedule defines a recurring time window.
+type ResourceSetInputSchedule struct {
+	// Cron specifies the start of the window with a standard
+	// five-field cron expression, e.g. '0 9 * * 1-5'.
+	// +required
+	Cron string `json:"cron"`
+
+	// TimeZone specifies the IANA time zone in which the cron
+	// expression is evaluated, e.g. 'Europe/London'. Defaults to 'UTC'.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Window specifies how long the window stays open after
+	// each start time, e.g. '8h'.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +required
+	Window metav1.Duration `json:"window"`
+}
+
+// ResourceSetInputExpiry defines when the input sets expire.
+// +kubebuilder:validation:XValidation:rule="has(self.maxAge) || has(self.maxInactivity)",message="at least one of maxAge or maxInactivity must be set"
+type ResourceSetInputExpiry struct {
+	// MaxAge drops the input sets with the head commit older than
+	// the given duration, e.g. '720h'. The age is computed from the
+	// 'commitTimestamp' input, the input sets without it never expire by age.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// MaxInactivity drops the input sets that have not changed
+	// for the given duration, e.g. '168h'.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	MaxInactivity *metav1.Duration `json:"maxInactivity,omitempty"`
+
+	// KeepLabel is the label that opts out the input sets from expiry,
+	// the input sets with this label in the 'labels' input never expire.
+	// +optional
+	KeepLabel string `json:"keepLabel,omitempty"`
+
+	// WarningBefore specifies how long before the expiry an event is
+	// emitted for the expiring input sets. Defaults to '24h'.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	WarningBefore *metav1.Duration `json:"warningBefore,omitempty"`
+}
+
+// ResourceSetInputApproval defines how the pull/merge requests are approved.
+// +kubebuilder:validation:XValidation:rule="!(has(self.label) || (has(self.review) && self.review)) || (has(self.approvers) && size(self.approvers) > 0)",message="approvers are required for the label and review approvals"
+type ResourceSetInputApproval struct {
+	// Label approves the pull/merge requests that
+	// have this label applied by one of the approvers.
+	// +optional
+	Label string `json:"label,omitempty"`
+
+	// Review approves the pull/merge requests that
+	// have an approving review from one of the approvers.
+	// +optional
+	Review bool `json:"review,omitempty"`
+
+	// Approvers is the list of usernames allowed to
+	// approve the pull/merge requests with a label or review.
+	// +optional
+	Approvers []string `json:"approvers,omitempty"`
+
+	// ReapproveOnNewCommit requires a new approval
+	// when the head commit of an approved pull/merge request changes.
+	// +optional
+	ReapproveOnNewCommit bool `json:"reapproveOnNewCommit,omitempty"`
+}
+
 // ResourceSetInputProviderStatus defines the observed state of ResourceSetInputProvider.
 type ResourceSetInputProviderStatus struct {
 	meta.ReconcileRequestStatus `json:",inline"`
@@ -114,6 +514,136 @@ type ResourceSetInputProviderStatus struct {
 	// inputs that were last reconcile.
 	// +optional
 	LastExportedRevision string `json:"lastExportedRevision,omitempty"`
+
+	// SourceRevision is the revision of the file from which the inputs
+	// were exported, the Git commit SHA for the GitHubFile and GitLabFile
+	// types, or the artifact revision for the FluxArtifact type.
+	// +optional
+	SourceRevision string `json:"sourceRevision,omitempty"`
+
+	// DroppedResults is the number of results returned by the
+	// Git provider that were not exported due to the limit filter.
+	// +optional
+	DroppedResults int `json:"droppedResults,omitempty"`
+
+	// PendingRevision is the digest of the inputs fetched outside
+	// the schedule windows that are waiting to be exported.
+	// +optional
+	PendingRevision string `json:"pendingRevision,omitempty"`
+
+	// ExportHistory contains the most recent revisions of the
+	// exported inputs, ordered from the newest to the oldest.
+	// +optional
+	ExportHistory []InputsExport `json:"exportHistory,omitempty"`
+
+	// InputsActivity records when each input set fetched from the
+	// provider last changed and when it expires, if an expiry is set.
+	// +optional
+	InputsActivity []InputActivity `json:"inputsActivity,omitempty"`
+
+	// PendingInputs contains the input sets waiting for approval.
+	// +optional
+	PendingInputs []ResourceSetInput `json:"pendingInputs,omitempty"`
+
+	// ApprovedInputs records the approvals of the input sets.
+	// +optional
+	ApprovedInputs []InputApproval `json:"approvedInputs,omitempty"`
+
+	// InputCommits records when the head commit of each input set was
+	// first seen, used to check that the approval label is applied
+	// after the head commit when re-approval on new commits is required.
+	// +optional
+	InputCommits []InputCommit `json:"inputCommits,omitempty"`
+}
+
+// InputApproval records the approval of an input set.
+type InputApproval struct {
+	// ID is the id of the input set.
+	// +required
+	ID string `json:"id"`
+
+	// SHA is the head commit SHA of the approved input set.
+	// +optional
+	SHA string `json:"sha,omitempty"`
+
+	// Method is the approval method, one of 'Label', 'Review' or 'Annotation'.
+	// +required
+	Method string `json:"method"`
+
+	// ApprovedBy is the username of the approver,
+	// empty for the approvals by annotation.
+	// +optional
+	ApprovedBy string `json:"approvedBy,omitempty"`
+
+	// ApprovedAt is the time when the approval was recorded.
+	// +required
+	ApprovedAt metav1.Time `json:"approvedAt"`
+}
+
+// InputCommit records the head commit of an input set.
+type InputCommit struct {
+	// ID is the id of the input set.
+	// +required
+	ID string `json:"id"`
+
+	// SHA is the head commit SHA of the input set.
+	// +required
+	SHA string `json:"sha"`
+
+	// FirstSeen is the time when the head commit was first seen.
+	// +required
+	FirstSeen metav1.Time `json:"firstSeen"`
+}
+
+// InputActivity records the activity of an input set.
+type InputActivity struct {
+	// ID is the id of the input set.
+	// +required
+	ID string `json:"id"`
+
+	// Digest is the digest of the input set, or of its
+	// 'sha' input, used to detect the input set changes.
+	// +required
+	Digest string `json:"digest"`
+
+	// LastChanged is the time when the input set last changed.
+	// +required
+	LastChanged metav1.Time `json:"lastChanged"`
+
+	// ExpiresAt is the time when the input set expires.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Expired is true if the input set is dropped from the exported inputs.
+	// +optional
+	Expired bool `json:"expired,omitempty"`
+
+	// Warned is true if the expiry event was emitted for the input set.
+	// +optional
+	Warned bool `json:"warned,omitempty"`
+}
+
+// InputsExport records a change of the exported inputs.
+type InputsExport struct {
+	// Revision is the digest of the exported inputs.
+	// +required
+	Revision string `json:"revision"`
+
+	// ExportedAt is the time when the inputs were exported.
+	// +required
+	ExportedAt metav1.Time `json:"exportedAt"`
+
+	// Added contains the ids of the input sets added in this revision.
+	// +optional
+	Added []string `json:"added,omitempty"`
+
+	// Updated contains the ids of the input sets updated in this revision.
+	// +optional
+	Updated []string `json:"updated,omitempty"`
+
+	// Removed contains the ids of the input sets removed in this revision.
+	// +optional
+	Removed []string `json:"removed,omitempty"`
 }
 
 // GetConditions returns the status conditions of the object.
@@ -166,6 +696,15 @@ func (in *ResourceSetInputProvider) GetTimeout() time.Duration {
 	return timeout
 }
 
+// GetURLs returns the addresses of the repositories queried by the provider,
+// either the list of URLs or the single URL when the list is not set.
+func (in *ResourceSetInputProvider) GetURLs() []string {
+	if len(in.Spec.URLs) > 0 {
+		return in.Spec.URLs
+	}
+	return []string{in.Spec.URL}
+}
+
 // GetDefaultInputs returns the ResourceSetInputProvider default inputs.
 func (in *ResourceSetInputProvider) GetDefaultInputs() (map[string]any, error) {
 	defaults := make(map[string]any)
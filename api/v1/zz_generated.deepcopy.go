@@ -446,9 +446,66 @@ func (in *FluxSyncStatus) DeepCopy() *FluxSyncStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InputActivity) DeepCopyInto(out *InputActivity) {
+	*out = *in
+	in.LastChanged.DeepCopyInto(&out.LastChanged)
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InputActivity.
+func (in *InputActivity) DeepCopy() *InputActivity {
+	if in == nil {
+		return nil
+	}
+	out := new(InputActivity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InputApproval) DeepCopyInto(out *InputApproval) {
+	*out = *in
+	in.ApprovedAt.DeepCopyInto(&out.ApprovedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InputApproval.
+func (in *InputApproval) DeepCopy() *InputApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(InputApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InputCommit) DeepCopyInto(out *InputCommit) {
+	*out = *in
+	in.FirstSeen.DeepCopyInto(&out.FirstSeen)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InputCommit.
+func (in *InputCommit) DeepCopy() *InputCommit {
+	if in == nil {
+		return nil
+	}
+	out := new(InputCommit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InputProviderReference) DeepCopyInto(out *InputProviderReference) {
 	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InputProviderReference.
@@ -461,6 +518,52 @@ func (in *InputProviderReference) DeepCopy() *InputProviderReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InputStrategySpec) DeepCopyInto(out *InputStrategySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InputStrategySpec.
+func (in *InputStrategySpec) DeepCopy() *InputStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InputStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InputsExport) DeepCopyInto(out *InputsExport) {
+	*out = *in
+	in.ExportedAt.DeepCopyInto(&out.ExportedAt)
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Updated != nil {
+		in, out := &in.Updated, &out.Updated
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InputsExport.
+func (in *InputsExport) DeepCopy() *InputsExport {
+	if in == nil {
+		return nil
+	}
+	out := new(InputsExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kustomize) DeepCopyInto(out *Kustomize) {
 	*out = *in
@@ -575,6 +678,71 @@ func (in ResourceSetInput) DeepCopy() ResourceSetInput {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSetInputApproval) DeepCopyInto(out *ResourceSetInputApproval) {
+	*out = *in
+	if in.Approvers != nil {
+		in, out := &in.Approvers, &out.Approvers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSetInputApproval.
+func (in *ResourceSetInputApproval) DeepCopy() *ResourceSetInputApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSetInputApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSetInputExpiry) DeepCopyInto(out *ResourceSetInputExpiry) {
+	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxInactivity != nil {
+		in, out := &in.MaxInactivity, &out.MaxInactivity
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.WarningBefore != nil {
+		in, out := &in.WarningBefore, &out.WarningBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSetInputExpiry.
+func (in *ResourceSetInputExpiry) DeepCopy() *ResourceSetInputExpiry {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSetInputExpiry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSetInputFile) DeepCopyInto(out *ResourceSetInputFile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSetInputFile.
+func (in *ResourceSetInputFile) DeepCopy() *ResourceSetInputFile {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSetInputFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSetInputFilter) DeepCopyInto(out *ResourceSetInputFilter) {
 	*out = *in
@@ -583,6 +751,26 @@ func (in *ResourceSetInputFilter) DeepCopyInto(out *ResourceSetInputFilter) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.IncludeAuthors != nil {
+		in, out := &in.IncludeAuthors, &out.IncludeAuthors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeAuthors != nil {
+		in, out := &in.ExcludeAuthors, &out.ExcludeAuthors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSetInputFilter.
@@ -595,6 +783,28 @@ func (in *ResourceSetInputFilter) DeepCopy() *ResourceSetInputFilter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSetInputJSON) DeepCopyInto(out *ResourceSetInputJSON) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSetInputJSON.
+func (in *ResourceSetInputJSON) DeepCopy() *ResourceSetInputJSON {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSetInputJSON)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSetInputProvider) DeepCopyInto(out *ResourceSetInputProvider) {
 	*out = *in
@@ -657,6 +867,31 @@ func (in *ResourceSetInputProviderList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSetInputProviderSpec) DeepCopyInto(out *ResourceSetInputProviderSpec) {
 	*out = *in
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(ResourceSetInputSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JSON != nil {
+		in, out := &in.JSON, &out.JSON
+		*out = new(ResourceSetInputJSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(ResourceSetInputFile)
+		**out = **in
+	}
+	if in.SourceRef != nil {
+		in, out := &in.SourceRef, &out.SourceRef
+		*out = new(ResourceSetInputSourceReference)
+		**out = **in
+	}
 	if in.SecretRef != nil {
 		in, out := &in.SecretRef, &out.SecretRef
 		*out = new(meta.LocalObjectReference)
@@ -693,6 +928,28 @@ func (in *ResourceSetInputProviderSpec) DeepCopyInto(out *ResourceSetInputProvid
 		*out = new(ResourceSetInputSkip)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Transform != nil {
+		in, out := &in.Transform, &out.Transform
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = make([]ResourceSetInputSchedule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Expiry != nil {
+		in, out := &in.Expiry, &out.Expiry
+		*out = new(ResourceSetInputExpiry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(ResourceSetInputApproval)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSetInputProviderSpec.
@@ -738,6 +995,56 @@ func (in *ResourceSetInputProviderStatus) DeepCopyInto(out *ResourceSetInputProv
 			}
 		}
 	}
+	if in.ExportHistory != nil {
+		in, out := &in.ExportHistory, &out.ExportHistory
+		*out = make([]InputsExport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InputsActivity != nil {
+		in, out := &in.InputsActivity, &out.InputsActivity
+		*out = make([]InputActivity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingInputs != nil {
+		in, out := &in.PendingInputs, &out.PendingInputs
+		*out = make([]ResourceSetInput, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(ResourceSetInput, len(*in))
+				for key, val := range *in {
+					var outVal *apiextensionsv1.JSON
+					if val == nil {
+						(*out)[key] = nil
+					} else {
+						inVal := (*in)[key]
+						in, out := &inVal, &outVal
+						*out = new(apiextensionsv1.JSON)
+						(*in).DeepCopyInto(*out)
+					}
+					(*out)[key] = outVal
+				}
+			}
+		}
+	}
+	if in.ApprovedInputs != nil {
+		in, out := &in.ApprovedInputs, &out.ApprovedInputs
+		*out = make([]InputApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InputCommits != nil {
+		in, out := &in.InputCommits, &out.InputCommits
+		*out = make([]InputCommit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSetInputProviderStatus.
@@ -750,6 +1057,49 @@ func (in *ResourceSetInputProviderStatus) DeepCopy() *ResourceSetInputProviderSt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSetInputSchedule) DeepCopyInto(out *ResourceSetInputSchedule) {
+	*out = *in
+	out.Window = in.Window
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSetInputSchedule.
+func (in *ResourceSetInputSchedule) DeepCopy() *ResourceSetInputSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSetInputSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSetInputSelector) DeepCopyInto(out *ResourceSetInputSelector) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSetInputSelector.
+func (in *ResourceSetInputSelector) DeepCopy() *ResourceSetInputSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSetInputSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSetInputSkip) DeepCopyInto(out *ResourceSetInputSkip) {
 	*out = *in
@@ -770,6 +1120,21 @@ func (in *ResourceSetInputSkip) DeepCopy() *ResourceSetInputSkip {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSetInputSourceReference) DeepCopyInto(out *ResourceSetInputSourceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSetInputSourceReference.
+func (in *ResourceSetInputSourceReference) DeepCopy() *ResourceSetInputSourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSetInputSourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSetList) DeepCopyInto(out *ResourceSetList) {
 	*out = *in
@@ -835,7 +1200,19 @@ func (in *ResourceSetSpec) DeepCopyInto(out *ResourceSetSpec) {
 	if in.InputsFrom != nil {
 		in, out := &in.InputsFrom, &out.InputsFrom
 		*out = make([]InputProviderReference, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InputStrategy != nil {
+		in, out := &in.InputStrategy, &out.InputStrategy
+		*out = new(InputStrategySpec)
+		**out = **in
+	}
+	if in.InputsSchema != nil {
+		in, out := &in.InputsSchema, &out.InputsSchema
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
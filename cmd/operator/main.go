@@ -36,6 +36,7 @@ import (
 	"github.com/controlplaneio-fluxcd/flux-operator/internal/controller"
 	"github.com/controlplaneio-fluxcd/flux-operator/internal/entitlement"
 	"github.com/controlplaneio-fluxcd/flux-operator/internal/reporter"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/webhook"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -58,6 +59,7 @@ func main() {
 		defaultServiceAccountEnvKey = "DEFAULT_SERVICE_ACCOUNT"
 		reportingIntervalEnvKey     = "REPORTING_INTERVAL"
 		runtimeNamespaceEnvKey      = "RUNTIME_NAMESPACE"
+		webhookSecretEnvKey         = "WEBHOOK_RECEIVER_SECRET"
 		tokenCacheDefaultMaxSize    = 100
 	)
 
@@ -67,11 +69,13 @@ func main() {
 		tokenCacheOptions     cache.TokenFlags
 		metricsAddr           string
 		healthAddr            string
+		webhookAddr           string
 		enableLeaderElection  bool
 		logOptions            logger.Options
 		rateLimiterOptions    runtimeCtrl.RateLimiterOptions
 		storagePath           string
 		defaultServiceAccount string
+		noCrossNamespaceRefs  bool
 	)
 
 	flag.IntVar(&concurrent, "concurrent", 10,
@@ -82,10 +86,14 @@ func main() {
 		"The address the metric endpoint binds to.")
 	flag.StringVar(&healthAddr, "health-addr", ":8081",
 		"The address the health endpoint binds to.")
+	flag.StringVar(&webhookAddr, "webhook-receiver-addr", "",
+		"The address the webhook receiver endpoint binds to, the receiver is disabled if not set.")
 	flag.StringVar(&storagePath, "storage-path", "/data",
 		"The local storage path.")
 	flag.StringVar(&defaultServiceAccount, "default-service-account", "",
 		"Default service account used for impersonation.")
+	flag.BoolVar(&noCrossNamespaceRefs, "no-cross-namespace-refs", false,
+		"When set to true, ResourceSets can reference input providers only from their own namespace.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", true,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -263,6 +271,8 @@ func main() {
 		StatusManager:         controllerName,
 		EventRecorder:         mgr.GetEventRecorderFor(controllerName),
 		DefaultServiceAccount: defaultServiceAccount,
+		NoCrossNamespaceRefs:  noCrossNamespaceRefs,
+		TokenCache:            tokenCache,
 	}).SetupWithManager(ctx, mgr,
 		controller.ResourceSetReconcilerOptions{
 			RateLimiter: runtimeCtrl.GetRateLimiter(rateLimiterOptions),
@@ -277,7 +287,7 @@ func main() {
 		StatusManager: controllerName,
 		EventRecorder: mgr.GetEventRecorderFor(controllerName),
 		TokenCache:    tokenCache,
-	}).SetupWithManager(mgr,
+	}).SetupWithManager(ctx, mgr,
 		controller.ResourceSetInputProviderReconcilerOptions{
 			RateLimiter: runtimeCtrl.GetRateLimiter(rateLimiterOptions),
 		}); err != nil {
@@ -286,6 +296,24 @@ func main() {
 	}
 	// +kubebuilder:scaffold:builder
 
+	if webhookAddr != "" {
+		webhookSecret := os.Getenv(webhookSecretEnvKey)
+		if webhookSecret == "" {
+			setupLog.Error(errors.New(webhookSecretEnvKey+" env var not set"), "unable to create webhook receiver")
+			os.Exit(1)
+		}
+
+		webhook.MustRegisterMetrics()
+		if err := mgr.Add(&webhook.Receiver{
+			Client:  mgr.GetClient(),
+			Address: webhookAddr,
+			Secret:  []byte(webhookSecret),
+		}); err != nil {
+			setupLog.Error(err, "unable to create webhook receiver")
+			os.Exit(1)
+		}
+	}
+
 	probes.SetupChecks(mgr, setupLog)
 
 	setupLog.Info("starting manager")
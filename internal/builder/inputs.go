@@ -0,0 +1,103 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package builder
+
+import (
+	"fmt"
+	"hash/adler32"
+	"regexp"
+	"strings"
+)
+
+// ProviderInputs holds the input sets exported by an input provider.
+type ProviderInputs struct {
+	// Name of the input provider.
+	Name string
+
+	// Key under which the input sets are nested when permuted.
+	// When not set, the key is computed from the provider name.
+	Key string
+
+	// Inputs is the list of input sets exported by the provider.
+	Inputs []map[string]any
+}
+
+var nonAlphanumericRegexp = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// InputsKey returns the key under which the input sets of the given provider
+// are nested when permuted. The provider name is lowercased and the characters
+// not allowed in template field names are replaced with underscores.
+func InputsKey(name string) string {
+	return nonAlphanumericRegexp.ReplaceAllString(strings.ToLower(name), "_")
+}
+
+// PermuteInputs returns the Cartesian product of the input sets
+// exported by the given providers. In each resulting input set,
+// the input set of a provider is nested under the provider key
+// and the 'id' field is set to a checksum of the combined ids.
+// If any provider has no input sets, the result is empty.
+// It fails if two providers have the same key.
+func PermuteInputs(providers []ProviderInputs) ([]map[string]any, error) {
+	if len(providers) == 0 {
+		return []map[string]any{}, nil
+	}
+
+	keys := make([]string, len(providers))
+	found := make(map[string]string, len(providers))
+	for i, p := range providers {
+		key := p.Key
+		if key == "" {
+			key = InputsKey(p.Name)
+		}
+		if key == "id" {
+			return nil, fmt.Errorf("provider %s conflicts with the reserved key 'id'", p.Name)
+		}
+		if other, ok := found[key]; ok {
+			return nil, fmt.Errorf("providers %s and %s have the same key '%s', "+
+				"set a different key in inputsFrom", other, p.Name, key)
+		}
+		found[key] = p.Name
+		keys[i] = key
+	}
+
+	// Start with an empty combination and extend it with every
+	// input set of each provider, in the order of the providers.
+	type combination struct {
+		ids    []string
+		inputs map[string]any
+	}
+	combinations := []combination{{inputs: map[string]any{}}}
+	for i, p := range providers {
+		next := make([]combination, 0, len(combinations)*len(p.Inputs))
+		for _, c := range combinations {
+			for j, input := range p.Inputs {
+				id := fmt.Sprintf("%v", j)
+				if v, ok := input["id"]; ok {
+					id = fmt.Sprintf("%v", v)
+				}
+
+				inputs := make(map[string]any, len(c.inputs)+1)
+				for k, v := range c.inputs {
+					inputs[k] = v
+				}
+				inputs[keys[i]] = input
+
+				ids := make([]string, 0, len(c.ids)+1)
+				ids = append(ids, c.ids...)
+				ids = append(ids, fmt.Sprintf("%s=%s", keys[i], id))
+
+				next = append(next, combination{ids: ids, inputs: inputs})
+			}
+		}
+		combinations = next
+	}
+
+	result := make([]map[string]any, 0, len(combinations))
+	for _, c := range combinations {
+		c.inputs["id"] = fmt.Sprintf("%v", adler32.Checksum([]byte(strings.Join(c.ids, ","))))
+		result = append(result, c.inputs)
+	}
+
+	return result, nil
+}
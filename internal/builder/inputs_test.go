@@ -0,0 +1,129 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package builder
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPermuteInputs(t *testing.T) {
+	prs := ProviderInputs{
+		Name: "pr",
+		Inputs: []map[string]any{
+			{"id": "1", "branch": "feat-1"},
+			{"id": "2", "branch": "feat-2"},
+		},
+	}
+	regions := ProviderInputs{
+		Name: "region",
+		Inputs: []map[string]any{
+			{"id": "eu", "name": "eu-west-1"},
+			{"id": "us", "name": "us-east-1"},
+			{"id": "ap", "name": "ap-south-1"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		providers []ProviderInputs
+		expected  []map[string]any
+		err       string
+	}{
+		{
+			name:      "no providers",
+			providers: nil,
+			expected:  []map[string]any{},
+		},
+		{
+			name:      "single provider",
+			providers: []ProviderInputs{prs},
+			expected: []map[string]any{
+				{"id": "63242577", "pr": prs.Inputs[0]},
+				{"id": "63308114", "pr": prs.Inputs[1]},
+			},
+		},
+		{
+			name: "provider without inputs",
+			providers: []ProviderInputs{prs, {
+				Name:   "region",
+				Inputs: []map[string]any{},
+			}},
+			expected: []map[string]any{},
+		},
+		{
+			name:      "provider without ids",
+			providers: []ProviderInputs{{Name: "Tenants", Inputs: []map[string]any{{"tenant": "team1"}}}},
+			expected: []map[string]any{
+				{"id": "310772587", "tenants": map[string]any{"tenant": "team1"}},
+			},
+		},
+		{
+			name:      "reserved key",
+			providers: []ProviderInputs{{Name: "ID"}},
+			err:       "reserved key 'id'",
+		},
+		{
+			name:      "duplicate keys",
+			providers: []ProviderInputs{{Name: "app-pr"}, {Name: "app.pr"}},
+			err:       "providers app-pr and app.pr have the same key 'app_pr'",
+		},
+		{
+			name: "explicit keys",
+			providers: []ProviderInputs{
+				{Name: "pr", Inputs: prs.Inputs[:1]},
+				{Name: "pr", Key: "upstream_pr", Inputs: prs.Inputs[1:]},
+			},
+			expected: []map[string]any{
+				{"id": "1061226142", "pr": prs.Inputs[0], "upstream_pr": prs.Inputs[1]},
+			},
+		},
+		{
+			name: "duplicate explicit key",
+			providers: []ProviderInputs{
+				{Name: "pr"},
+				{Name: "app", Key: "pr"},
+			},
+			err: "providers pr and app have the same key 'pr'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, err := PermuteInputs(tt.providers)
+			if tt.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(tt.expected))
+		})
+	}
+
+	t.Run("cartesian product", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := PermuteInputs([]ProviderInputs{prs, regions})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(HaveLen(6))
+
+		ids := make(map[string]bool)
+		for i, input := range result {
+			g.Expect(input["pr"]).To(Equal(prs.Inputs[i/3]))
+			g.Expect(input["region"]).To(Equal(regions.Inputs[i%3]))
+			ids[input["id"].(string)] = true
+		}
+		g.Expect(ids).To(HaveLen(6))
+
+		// The ids are deterministic.
+		again, err := PermuteInputs([]ProviderInputs{prs, regions})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(again).To(Equal(result))
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// InputsSchema validates the ResourceSet inputs against an OpenAPI v3
+// schema and sets the default values of the missing fields.
+type InputsSchema struct {
+	schema    *spec.Schema
+	validator *validate.SchemaValidator
+}
+
+// NewInputsSchema parses the OpenAPI v3 schema from its JSON representation.
+func NewInputsSchema(data []byte) (*InputsSchema, error) {
+	schema := &spec.Schema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("failed to parse inputs schema: %w", err)
+	}
+
+	return &InputsSchema{
+		schema:    schema,
+		validator: validate.NewSchemaValidator(schema, nil, "", strfmt.Default),
+	}, nil
+}
+
+// Apply sets the default values defined in the schema for the
+// fields missing from the input, then validates the input.
+// It returns an error listing all the validation failures.
+func (s *InputsSchema) Apply(input map[string]any) error {
+	applyDefaults(s.schema, input)
+
+	result := s.validator.Validate(input)
+	if result == nil || result.IsValid() {
+		return nil
+	}
+
+	errs := make([]string, 0, len(result.Errors))
+	for _, err := range result.Errors {
+		errs = append(errs, strings.TrimPrefix(err.Error(), "."))
+	}
+	sort.Strings(errs)
+
+	return fmt.Errorf("%s", strings.Join(errs, ", "))
+}
+
+// applyDefaults sets the schema default values for the missing object
+// properties, by walking the value according to the schema.
+func applyDefaults(schema *spec.Schema, value any) {
+	if schema == nil {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for name, prop := range schema.Properties {
+			if _, ok := v[name]; !ok && prop.Default != nil {
+				v[name] = runtime.DeepCopyJSONValue(normalizeJSON(prop.Default))
+			}
+			if field, ok := v[name]; ok {
+				applyDefaults(&prop, field)
+			}
+		}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			for name, field := range v {
+				if _, ok := schema.Properties[name]; !ok {
+					applyDefaults(schema.AdditionalProperties.Schema, field)
+				}
+			}
+		}
+	case []any:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for _, item := range v {
+				applyDefaults(schema.Items.Schema, item)
+			}
+		}
+	}
+}
+
+// normalizeJSON converts the default value to the types
+// returned by the JSON decoder used for the inputs.
+func normalizeJSON(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return value
+	}
+	return out
+}
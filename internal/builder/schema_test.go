@@ -0,0 +1,108 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package builder
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestInputsSchema_Apply(t *testing.T) {
+	schema := `{
+  "type": "object",
+  "required": ["tenant"],
+  "properties": {
+    "tenant": {"type": "string", "pattern": "^[a-z]+$"},
+    "replicas": {"type": "integer", "minimum": 1, "default": 2},
+    "env": {"type": "string", "enum": ["dev", "prod"], "default": "dev"},
+    "cluster": {
+      "type": "object",
+      "default": {},
+      "properties": {
+        "region": {"type": "string", "default": "eu-west-1"}
+      }
+    },
+    "apps": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "version": {"type": "string", "default": "latest"}
+        }
+      }
+    }
+  }
+}`
+
+	tests := []struct {
+		name       string
+		input      map[string]any
+		want       map[string]any
+		wantErrMsg string
+	}{
+		{
+			name:  "sets defaults",
+			input: map[string]any{"tenant": "team", "apps": []any{map[string]any{"name": "podinfo"}}},
+			want: map[string]any{
+				"tenant":   "team",
+				"replicas": float64(2),
+				"env":      "dev",
+				"cluster":  map[string]any{"region": "eu-west-1"},
+				"apps":     []any{map[string]any{"name": "podinfo", "version": "latest"}},
+			},
+		},
+		{
+			name:  "keeps values",
+			input: map[string]any{"tenant": "team", "replicas": int64(3), "env": "prod", "cluster": map[string]any{"region": "us-east-1"}},
+			want: map[string]any{
+				"tenant":   "team",
+				"replicas": int64(3),
+				"env":      "prod",
+				"cluster":  map[string]any{"region": "us-east-1"},
+			},
+		},
+		{
+			name:       "missing required field",
+			input:      map[string]any{"env": "prod"},
+			wantErrMsg: "tenant in body is required",
+		},
+		{
+			name:       "multiple failures",
+			input:      map[string]any{"tenant": "Team", "replicas": int64(0), "env": "staging"},
+			wantErrMsg: "env in body should be one of [dev prod], replicas in body should be greater than or equal to 1, tenant in body should match '^[a-z]+$'",
+		},
+		{
+			name:       "invalid type",
+			input:      map[string]any{"tenant": "team", "replicas": "two"},
+			wantErrMsg: "replicas in body must be of type integer: \"string\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s, err := NewInputsSchema([]byte(schema))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			err = s.Apply(tt.input)
+			if tt.wantErrMsg != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(Equal(tt.wantErrMsg))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(tt.input).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNewInputsSchema_Invalid(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewInputsSchema([]byte(`{"type": 1}`))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to parse inputs schema"))
+}
@@ -226,9 +226,13 @@ func GetDistributionKeychain(ctx context.Context, kubeClient client.Client, obj
 		return nil, nil
 	}
 	// the secret must be defined in the same namespace as the FluxInstance resource
-	ns := obj.GetNamespace()
+	return GetPullSecretKeychain(ctx, kubeClient, artifactPullSecret, obj.GetNamespace())
+}
+
+// GetPullSecretKeychain returns a keychain from the docker-config secret with the given name and namespace.
+func GetPullSecretKeychain(ctx context.Context, kubeClient client.Client, name, namespace string) (authn.Keychain, error) {
 	secret := corev1.Secret{}
-	if err := kubeClient.Get(ctx, client.ObjectKey{Name: artifactPullSecret, Namespace: ns}, &secret); err != nil {
+	if err := kubeClient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, &secret); err != nil {
 		return nil, err
 	}
 	return k8schain.NewFromPullSecrets(ctx, []corev1.Secret{secret})
@@ -0,0 +1,161 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/gitprovider"
+)
+
+// reportCommitStatus reports the ResourceSet readiness as commit status on the
+// pull/merge requests exported by the input providers that have reportStatus enabled.
+// The status is reported only when the Ready condition or the exported inputs
+// of a provider changed since the last report. Errors are logged and do not
+// fail the reconciliation.
+func (r *ResourceSetReconciler) reportCommitStatus(ctx context.Context, obj *fluxcdv1.ResourceSet) {
+	log := ctrl.LoggerFrom(ctx)
+
+	status, ok := commitStatusFor(obj)
+	if !ok {
+		return
+	}
+
+	providers, err := r.getInputProviders(ctx, obj)
+	if err != nil {
+		return
+	}
+
+	for _, rsip := range providers {
+		if !rsip.Spec.ReportStatus {
+			continue
+		}
+
+		key := commitStatusKey(obj, rsip)
+		reported := fmt.Sprintf("%s/%s/%s", status.State, status.Description, rsip.Status.LastExportedRevision)
+		if last, ok := r.reportedStatuses.Load(key); ok && last == reported {
+			continue
+		}
+
+		reportCtx, cancel := context.WithTimeout(ctx, rsip.GetTimeout())
+		err := r.reportCommitStatusTo(reportCtx, rsip, status)
+		cancel()
+		if err != nil {
+			log.Error(err, "failed to report commit status",
+				"provider", fmt.Sprintf("%s/%s", rsip.GetNamespace(), rsip.GetName()))
+			continue
+		}
+
+		r.reportedStatuses.Store(key, reported)
+	}
+}
+
+// forgetCommitStatus removes the reported statuses of the ResourceSet.
+func (r *ResourceSetReconciler) forgetCommitStatus(obj *fluxcdv1.ResourceSet) {
+	prefix := fmt.Sprintf("%s/%s/", obj.GetNamespace(), obj.GetName())
+	r.reportedStatuses.Range(func(key, _ any) bool {
+		if strings.HasPrefix(key.(string), prefix) {
+			r.reportedStatuses.Delete(key)
+		}
+		return true
+	})
+}
+
+// commitStatusKey returns the key of the status reported
+// by the ResourceSet through the input provider.
+func commitStatusKey(obj *fluxcdv1.ResourceSet, rsip *fluxcdv1.ResourceSetInputProvider) string {
+	return fmt.Sprintf("%s/%s/%s/%s",
+		obj.GetNamespace(), obj.GetName(), rsip.GetNamespace(), rsip.GetName())
+}
+
+// reportCommitStatusTo reports the status on the head commit
+// of each pull/merge request exported by the input provider.
+func (r *ResourceSetReconciler) reportCommitStatusTo(ctx context.Context,
+	rsip *fluxcdv1.ResourceSetInputProvider,
+	status gitprovider.CommitStatus) error {
+	inputs, err := rsip.GetInputs()
+	if err != nil {
+		return err
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	// Reuse the input provider reconciler to read the
+	// credentials and create the Git provider client.
+	rp := &ResourceSetInputProviderReconciler{
+		Client:     r.Client,
+		TokenCache: r.TokenCache,
+	}
+
+	certPool, err := rp.getCertPool(ctx, rsip)
+	if err != nil {
+		return err
+	}
+
+	var authData map[string][]byte
+	if rsip.Spec.SecretRef != nil {
+		authData, err = rp.getSecretData(ctx, rsip.Spec.SecretRef.Name, rsip.GetNamespace())
+		if err != nil {
+			return err
+		}
+	}
+
+	provider, err := rp.newGitProvider(ctx, rsip, rsip.Spec.URL, certPool, authData)
+	if err != nil {
+		return err
+	}
+
+	statusReporter, ok := provider.(gitprovider.StatusReporter)
+	if !ok {
+		return fmt.Errorf("commit status reporting is not supported for type %s", rsip.Spec.Type)
+	}
+
+	for _, input := range inputs {
+		sha, ok := input["sha"].(string)
+		if !ok || sha == "" {
+			continue
+		}
+		if err := statusReporter.ReportStatus(ctx, sha, status); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// commitStatusFor returns the commit status computed from the ResourceSet
+// Ready condition. It returns false if the Ready condition is not set.
+func commitStatusFor(obj *fluxcdv1.ResourceSet) (gitprovider.CommitStatus, bool) {
+	ready := conditions.Get(obj, meta.ReadyCondition)
+	if ready == nil {
+		return gitprovider.CommitStatus{}, false
+	}
+
+	status := gitprovider.CommitStatus{
+		Context:     fmt.Sprintf("flux-operator/%s/%s", obj.GetNamespace(), obj.GetName()),
+		State:       gitprovider.CommitStatusPending,
+		Description: ready.Message,
+	}
+
+	switch {
+	case ready.Status == metav1.ConditionTrue:
+		// The success message contains the reconciliation duration,
+		// a fixed description avoids reporting the same status again.
+		status.State = gitprovider.CommitStatusSuccess
+		status.Description = "Reconciliation succeeded"
+	case ready.Status == metav1.ConditionFalse && ready.Reason != meta.DependencyNotReadyReason:
+		status.State = gitprovider.CommitStatusFailure
+	}
+
+	return status, true
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/gitprovider"
+)
+
+func TestCommitStatusFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    metav1.ConditionStatus
+		reason    string
+		message   string
+		wantState gitprovider.CommitStatusState
+		wantDesc  string
+	}{
+		{
+			name:      "ready",
+			status:    metav1.ConditionTrue,
+			reason:    meta.ReconciliationSucceededReason,
+			message:   "Reconciliation finished in 2s",
+			wantState: gitprovider.CommitStatusSuccess,
+			wantDesc:  "Reconciliation succeeded",
+		},
+		{
+			name:      "failed",
+			status:    metav1.ConditionFalse,
+			reason:    meta.ReconciliationFailedReason,
+			message:   "health check failed",
+			wantState: gitprovider.CommitStatusFailure,
+			wantDesc:  "health check failed",
+		},
+		{
+			name:      "dependency not ready",
+			status:    metav1.ConditionFalse,
+			reason:    meta.DependencyNotReadyReason,
+			message:   "dependency apps/infra not ready",
+			wantState: gitprovider.CommitStatusPending,
+			wantDesc:  "dependency apps/infra not ready",
+		},
+		{
+			name:      "progressing",
+			status:    metav1.ConditionUnknown,
+			reason:    meta.ProgressingReason,
+			message:   "reconciliation in progress",
+			wantState: gitprovider.CommitStatusPending,
+			wantDesc:  "reconciliation in progress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &fluxcdv1.ResourceSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "preview", Namespace: "apps"},
+			}
+			conditions.Set(obj, &metav1.Condition{
+				Type:    meta.ReadyCondition,
+				Status:  tt.status,
+				Reason:  tt.reason,
+				Message: tt.message,
+			})
+
+			status, ok := commitStatusFor(obj)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(status.Context).To(Equal("flux-operator/apps/preview"))
+			g.Expect(status.State).To(Equal(tt.wantState))
+			g.Expect(status.Description).To(Equal(tt.wantDesc))
+		})
+	}
+
+	t.Run("without ready condition", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &fluxcdv1.ResourceSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "preview", Namespace: "apps"},
+		}
+
+		_, ok := commitStatusFor(obj)
+		g.Expect(ok).To(BeFalse())
+	})
+}
+
+func TestResourceSetReconciler_ReportCommitStatus(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	var created atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/org/app/commits/a1/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"state":"pending","statuses":[]}`))
+	})
+	mux.HandleFunc("POST /api/v3/repos/org/app/statuses/a1", func(w http.ResponseWriter, r *http.Request) {
+		created.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	rsip := &fluxcdv1.ResourceSetInputProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-prs", Namespace: "apps"},
+		Spec: fluxcdv1.ResourceSetInputProviderSpec{
+			Type:         fluxcdv1.InputProviderGitHubPullRequest,
+			URL:          srv.URL + "/org/app",
+			ReportStatus: true,
+		},
+		Status: fluxcdv1.ResourceSetInputProviderStatus{
+			ExportedInputs: []fluxcdv1.ResourceSetInput{{
+				"id":  &apiextensionsv1.JSON{Raw: []byte(`"1"`)},
+				"sha": &apiextensionsv1.JSON{Raw: []byte(`"a1"`)},
+			}},
+			LastExportedRevision: "sha256:1",
+		},
+	}
+
+	obj := &fluxcdv1.ResourceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "preview", Namespace: "apps"},
+		Spec: fluxcdv1.ResourceSetSpec{
+			InputsFrom: []fluxcdv1.InputProviderReference{{
+				Kind: fluxcdv1.ResourceSetInputProviderKind,
+				Name: rsip.GetName(),
+			}},
+		},
+	}
+	conditions.MarkTrue(obj, meta.ReadyCondition, meta.ReconciliationSucceededReason, "Reconciliation finished in 1s")
+
+	r := &ResourceSetReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(NewTestScheme()).
+			WithObjects(rsip).
+			WithStatusSubresource(rsip).
+			Build(),
+	}
+
+	// Report the status once for the same Ready condition.
+	r.reportCommitStatus(ctx, obj)
+	r.reportCommitStatus(ctx, obj)
+	g.Expect(created.Load()).To(BeEquivalentTo(1))
+
+	// Report the status again when the Ready condition changes.
+	conditions.MarkFalse(obj, meta.ReadyCondition, meta.ReconciliationFailedReason, "health check failed")
+	r.reportCommitStatus(ctx, obj)
+	r.reportCommitStatus(ctx, obj)
+	g.Expect(created.Load()).To(BeEquivalentTo(2))
+
+	// Report the status again after forgetting the reported statuses.
+	r.forgetCommitStatus(obj)
+	r.reportCommitStatus(ctx, obj)
+	g.Expect(created.Load()).To(BeEquivalentTo(3))
+}
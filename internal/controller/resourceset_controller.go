@@ -7,13 +7,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/engine"
 	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
 	"github.com/fluxcd/cli-utils/pkg/object"
 	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/cache"
 	"github.com/fluxcd/pkg/runtime/cel"
 	runtimeClient "github.com/fluxcd/pkg/runtime/client"
 	"github.com/fluxcd/pkg/runtime/conditions"
@@ -53,6 +56,12 @@ type ResourceSetReconciler struct {
 
 	StatusManager         string
 	DefaultServiceAccount string
+	NoCrossNamespaceRefs  bool
+	TokenCache            *cache.TokenCache
+
+	// reportedStatuses holds the last commit status
+	// reported for each ResourceSet and input provider.
+	reportedStatuses sync.Map
 }
 
 // +kubebuilder:rbac:groups=fluxcd.controlplane.io,resources=resourcesets,verbs=get;list;watch;create;update;patch;delete
@@ -82,6 +91,12 @@ func (r *ResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		if err := r.recordMetrics(obj); err != nil {
 			log.Error(err, "failed to record metrics")
 		}
+
+		if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+			r.forgetCommitStatus(obj)
+		} else if !obj.IsDisabled() {
+			r.reportCommitStatus(ctx, obj)
+		}
 	}()
 
 	// Uninstall if the object is under deletion.
@@ -162,6 +177,40 @@ func (r *ResourceSetReconciler) reconcile(ctx context.Context,
 	// Compute the final inputs from providers and in-line inputs.
 	inputs, err := r.getInputs(ctx, obj)
 	if err != nil {
+		// Stall the reconciliation until the inputs or the schema are changed.
+		var validationErr *inputsValidationError
+		if errors.As(err, &validationErr) {
+			msg := fmt.Sprintf("invalid inputs: %s", err.Error())
+			conditions.MarkFalse(obj,
+				meta.ReadyCondition,
+				fluxcdv1.InvalidInputsReason,
+				"%s", msg)
+			conditions.MarkTrue(obj,
+				meta.StalledCondition,
+				fluxcdv1.InvalidInputsReason,
+				"%s", msg)
+			log.Error(err, "invalid inputs")
+			r.notify(ctx, obj, corev1.EventTypeWarning, fluxcdv1.InvalidInputsReason, msg)
+			return ctrl.Result{}, nil
+		}
+
+		// Stall the reconciliation until the references are changed.
+		var accessErr *accessDeniedError
+		if errors.As(err, &accessErr) {
+			msg := fmt.Sprintf("access denied: %s", err.Error())
+			conditions.MarkFalse(obj,
+				meta.ReadyCondition,
+				fluxcdv1.AccessDeniedReason,
+				"%s", msg)
+			conditions.MarkTrue(obj,
+				meta.StalledCondition,
+				fluxcdv1.AccessDeniedReason,
+				"%s", msg)
+			log.Error(err, "access denied")
+			r.notify(ctx, obj, corev1.EventTypeWarning, fluxcdv1.AccessDeniedReason, msg)
+			return ctrl.Result{}, nil
+		}
+
 		msg := fmt.Sprintf("failed to compute inputs: %s", err.Error())
 		conditions.MarkFalse(obj,
 			meta.ReadyCondition,
@@ -296,42 +345,179 @@ func (r *ResourceSetReconciler) checkDependencies(ctx context.Context,
 
 func (r *ResourceSetReconciler) getInputs(ctx context.Context,
 	obj *fluxcdv1.ResourceSet) ([]map[string]any, error) {
-	providers := make([]fluxcdv1.InputProvider, 0)
+	inputProviders, err := r.getInputProviders(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make([]fluxcdv1.InputProvider, 0, len(inputProviders)+1)
 	providers = append(providers, obj)
-	for _, inputSource := range obj.Spec.InputsFrom {
-		var provider fluxcdv1.InputProvider
-		key := client.ObjectKey{
-			Namespace: obj.GetNamespace(),
-			Name:      inputSource.Name,
-		}
+	for _, provider := range inputProviders {
+		providers = append(providers, provider)
+	}
 
-		switch inputSource.Kind {
-		case fluxcdv1.ResourceSetInputProviderKind:
-			var rsip fluxcdv1.ResourceSetInputProvider
-			if err := r.Get(ctx, key, &rsip); err != nil {
-				return nil, fmt.Errorf("failed to get provider %s/%s: %w", key.Namespace, key.Name, err)
-			}
-			provider = &rsip
-		default:
-			return nil, fmt.Errorf("unsupported provider kind %s", inputSource.Kind)
+	var schema *builder.InputsSchema
+	if obj.Spec.InputsSchema != nil {
+		schema, err = builder.NewInputsSchema(obj.Spec.InputsSchema.Raw)
+		if err != nil {
+			return nil, &inputsValidationError{errs: []string{err.Error()}}
 		}
+	}
 
-		providers = append(providers, provider)
+	permute := obj.Spec.InputStrategy != nil &&
+		obj.Spec.InputStrategy.Name == fluxcdv1.InputStrategyPermute
+
+	// Collect the keys set in inputsFrom for the permutation.
+	keys := make(map[client.ObjectKey]string)
+	for _, inputSource := range obj.Spec.InputsFrom {
+		if inputSource.Key == "" {
+			continue
+		}
+		namespace := obj.GetNamespace()
+		if inputSource.Namespace != "" {
+			namespace = inputSource.Namespace
+		}
+		keys[client.ObjectKey{Namespace: namespace, Name: inputSource.Name}] = inputSource.Key
 	}
 
 	inputs := make([]map[string]any, 0)
-	for _, provider := range providers {
+	providerInputs := make([]builder.ProviderInputs, 0, len(providers))
+	var validationErrs []string
+	for i, provider := range providers {
 		exportedInputs, err := provider.GetInputs()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get inputs from %s/%s: %w",
 				provider.GroupVersionKind().Kind, provider.GetName(), err)
 		}
+
+		// Apply the schema defaults and validate each input set.
+		if schema != nil {
+			for j, input := range exportedInputs {
+				if err := schema.Apply(input); err != nil {
+					inputRef := fmt.Sprintf("inputs[%d]", j)
+					if id, ok := input["id"]; ok {
+						inputRef = fmt.Sprintf("id=%v", id)
+					}
+					validationErrs = append(validationErrs, fmt.Sprintf("%s/%s %s: %s",
+						provider.GroupVersionKind().Kind, provider.GetName(), inputRef, err.Error()))
+				}
+			}
+		}
+
 		inputs = append(inputs, exportedInputs...)
+
+		// The ResourceSet is the first provider and takes part
+		// in the permutation only if it has in-line inputs.
+		if i == 0 && len(exportedInputs) == 0 {
+			continue
+		}
+		var key string
+		if i > 0 {
+			key = keys[client.ObjectKey{Namespace: provider.GetNamespace(), Name: provider.GetName()}]
+		}
+		providerInputs = append(providerInputs, builder.ProviderInputs{
+			Name:   provider.GetName(),
+			Key:    key,
+			Inputs: exportedInputs,
+		})
+	}
+
+	if len(validationErrs) > 0 {
+		return nil, &inputsValidationError{errs: validationErrs}
+	}
+
+	if permute {
+		permuted, err := builder.PermuteInputs(providerInputs)
+		if err != nil {
+			return nil, &inputsValidationError{errs: []string{err.Error()}}
+		}
+		return permuted, nil
 	}
 
 	return inputs, nil
 }
 
+// getInputProviders returns the input providers referenced in inputsFrom by
+// name or by label selector. The providers matched by a selector are sorted
+// by name, and the providers referenced multiple times are returned once.
+func (r *ResourceSetReconciler) getInputProviders(ctx context.Context,
+	obj *fluxcdv1.ResourceSet) ([]*fluxcdv1.ResourceSetInputProvider, error) {
+	providers := make([]*fluxcdv1.ResourceSetInputProvider, 0, len(obj.Spec.InputsFrom))
+	found := make(map[client.ObjectKey]bool)
+	for _, inputSource := range obj.Spec.InputsFrom {
+		if inputSource.Kind != fluxcdv1.ResourceSetInputProviderKind {
+			return nil, fmt.Errorf("unsupported provider kind %s", inputSource.Kind)
+		}
+
+		namespace := obj.GetNamespace()
+		if inputSource.Namespace != "" {
+			namespace = inputSource.Namespace
+		}
+		if r.NoCrossNamespaceRefs && namespace != obj.GetNamespace() {
+			return nil, &accessDeniedError{
+				msg: fmt.Sprintf("cannot reference providers in namespace %s, cross-namespace references are disabled", namespace),
+			}
+		}
+
+		var matched []fluxcdv1.ResourceSetInputProvider
+		if inputSource.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(inputSource.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid provider selector: %w", err)
+			}
+
+			var list fluxcdv1.ResourceSetInputProviderList
+			if err := r.List(ctx, &list,
+				client.InNamespace(namespace),
+				client.MatchingLabelsSelector{Selector: selector}); err != nil {
+				return nil, fmt.Errorf("failed to list providers in namespace %s: %w", namespace, err)
+			}
+			matched = list.Items
+			sort.Slice(matched, func(i, j int) bool {
+				return matched[i].GetName() < matched[j].GetName()
+			})
+		} else {
+			var rsip fluxcdv1.ResourceSetInputProvider
+			key := client.ObjectKey{Namespace: namespace, Name: inputSource.Name}
+			if err := r.Get(ctx, key, &rsip); err != nil {
+				return nil, fmt.Errorf("failed to get provider %s/%s: %w", key.Namespace, key.Name, err)
+			}
+			matched = append(matched, rsip)
+		}
+
+		for i := range matched {
+			key := client.ObjectKeyFromObject(&matched[i])
+			if found[key] {
+				continue
+			}
+			found[key] = true
+			providers = append(providers, &matched[i])
+		}
+	}
+
+	return providers, nil
+}
+
+// accessDeniedError is returned when the input providers are referenced
+// from another namespace and cross-namespace references are disabled.
+type accessDeniedError struct {
+	msg string
+}
+
+func (e *accessDeniedError) Error() string {
+	return e.msg
+}
+
+// inputsValidationError is returned when the
+// inputs don't match the ResourceSet inputs schema.
+type inputsValidationError struct {
+	errs []string
+}
+
+func (e *inputsValidationError) Error() string {
+	return strings.Join(e.errs, "; ")
+}
+
 // apply reconciles the resources in the cluster by performing
 // a server-side apply, pruning of stale resources and waiting
 // for the resources to become ready.
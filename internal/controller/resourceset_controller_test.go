@@ -15,6 +15,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -641,6 +642,430 @@ spec:
 	g.Expect(conditions.GetMessage(result, meta.ReadyCondition)).To(ContainSubstring("failed to parse expression"))
 }
 
+func TestResourceSetReconciler_InputsSchema(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getResourceSetReconciler(t)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objDef := fmt.Sprintf(`
+apiVersion: fluxcd.controlplane.io/v1
+kind: ResourceSet
+metadata:
+  name: tenants
+  namespace: "%[1]s"
+spec:
+  inputsSchema:
+    type: object
+    required: ["tenant"]
+    properties:
+      tenant:
+        type: string
+      role:
+        type: string
+        enum: ["admin", "view"]
+        default: "view"
+  inputs:
+    - tenant: team1
+    - role: admin
+  resources:
+    - apiVersion: v1
+      kind: ConfigMap
+      metadata:
+        name: << inputs.tenant >>
+        namespace: "%[1]s"
+      data:
+        role: << inputs.role >>
+`, ns.Name)
+
+	obj := &fluxcdv1.ResourceSet{}
+	err = yaml.Unmarshal([]byte(objDef), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Initialize the instance.
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	// Reconcile with invalid inputs.
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.RequeueAfter).To(Equal(time.Duration(0)))
+
+	result := &fluxcdv1.ResourceSet{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.GetReason(result, meta.ReadyCondition)).To(BeIdenticalTo(fluxcdv1.InvalidInputsReason))
+	g.Expect(conditions.IsStalled(result)).To(BeTrue())
+	g.Expect(conditions.GetMessage(result, meta.ReadyCondition)).To(ContainSubstring("inputs[1]: tenant in body is required"))
+	g.Expect(result.Status.Inventory).To(BeNil())
+
+	// Fix the inputs.
+	resultP := result.DeepCopy()
+	resultP.Spec.Inputs[1] = fluxcdv1.ResourceSetInput{
+		"tenant": &apiextensionsv1.JSON{Raw: []byte(`"team2"`)},
+		"role":   &apiextensionsv1.JSON{Raw: []byte(`"admin"`)},
+	}
+	err = testClient.Patch(ctx, resultP, client.MergeFrom(result))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Reconcile with valid inputs.
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(conditions.IsReady(result)).To(BeTrue())
+	g.Expect(conditions.IsStalled(result)).To(BeFalse())
+
+	// Check if the default value was set.
+	cm := &corev1.ConfigMap{}
+	err = testClient.Get(ctx, client.ObjectKey{Name: "team1", Namespace: ns.Name}, cm)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cm.Data).To(HaveKeyWithValue("role", "view"))
+
+	err = testClient.Get(ctx, client.ObjectKey{Name: "team2", Namespace: ns.Name}, cm)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cm.Data).To(HaveKeyWithValue("role", "admin"))
+}
+
+func TestResourceSetReconciler_InputsFromSelector(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getResourceSetReconciler(t)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	providersNS, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Create the input providers with exported inputs.
+	for _, p := range []struct {
+		name string
+		team string
+	}{
+		{name: "app1", team: "dev"},
+		{name: "app2", team: "dev"},
+		{name: "app3", team: "ops"},
+	} {
+		rsip := &fluxcdv1.ResourceSetInputProvider{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      p.name,
+				Namespace: providersNS.Name,
+				Labels:    map[string]string{"team": p.team},
+			},
+			Spec: fluxcdv1.ResourceSetInputProviderSpec{
+				Type: fluxcdv1.InputProviderGitHubBranch,
+				URL:  "https://github.com/org/" + p.name,
+			},
+		}
+		err = testClient.Create(ctx, rsip)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		rsip.Status.ExportedInputs = []fluxcdv1.ResourceSetInput{{
+			"id":   &apiextensionsv1.JSON{Raw: []byte(fmt.Sprintf(`"%s"`, p.name))},
+			"team": &apiextensionsv1.JSON{Raw: []byte(fmt.Sprintf(`"%s"`, p.team))},
+		}}
+		err = testClient.Status().Update(ctx, rsip)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	objDef := fmt.Sprintf(`
+apiVersion: fluxcd.controlplane.io/v1
+kind: ResourceSet
+metadata:
+  name: apps
+  namespace: "%[1]s"
+spec:
+  inputsFrom:
+    - kind: ResourceSetInputProvider
+      namespace: "%[2]s"
+      selector:
+        matchLabels:
+          team: dev
+    - kind: ResourceSetInputProvider
+      namespace: "%[2]s"
+      name: app1
+  resources:
+    - apiVersion: v1
+      kind: ConfigMap
+      metadata:
+        name: << inputs.id >>
+        namespace: "%[1]s"
+      data:
+        team: << inputs.team >>
+`, ns.Name, providersNS.Name)
+
+	obj := &fluxcdv1.ResourceSet{}
+	err = yaml.Unmarshal([]byte(objDef), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Initialize the instance.
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	// Reconcile with the inputs from the selected providers.
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	result := &fluxcdv1.ResourceSet{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.IsReady(result)).To(BeTrue())
+	g.Expect(result.Status.Inventory.Entries).To(HaveLen(2))
+
+	cm := &corev1.ConfigMap{}
+	err = testClient.Get(ctx, client.ObjectKey{Name: "app1", Namespace: ns.Name}, cm)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = testClient.Get(ctx, client.ObjectKey{Name: "app2", Namespace: ns.Name}, cm)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = testClient.Get(ctx, client.ObjectKey{Name: "app3", Namespace: ns.Name}, cm)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// Reconcile with cross-namespace references disabled.
+	reconciler.NoCrossNamespaceRefs = true
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.RequeueAfter).To(Equal(time.Duration(0)))
+
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.GetReason(result, meta.ReadyCondition)).To(BeIdenticalTo(fluxcdv1.AccessDeniedReason))
+	g.Expect(conditions.IsStalled(result)).To(BeTrue())
+	g.Expect(conditions.GetMessage(result, meta.ReadyCondition)).To(ContainSubstring(providersNS.Name))
+}
+
+func TestResourceSetReconciler_InputStrategyPermute(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getResourceSetReconciler(t)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Create the input providers with exported inputs.
+	for name, values := range map[string][]string{
+		"pr":     {"1", "2"},
+		"region": {"eu", "us", "ap"},
+	} {
+		rsip := &fluxcdv1.ResourceSetInputProvider{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns.Name,
+			},
+			Spec: fluxcdv1.ResourceSetInputProviderSpec{
+				Type: fluxcdv1.InputProviderGitHubBranch,
+				URL:  "https://github.com/org/" + name,
+			},
+		}
+		err = testClient.Create(ctx, rsip)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for _, v := range values {
+			rsip.Status.ExportedInputs = append(rsip.Status.ExportedInputs, fluxcdv1.ResourceSetInput{
+				"id": &apiextensionsv1.JSON{Raw: []byte(fmt.Sprintf(`"%s"`, v))},
+			})
+		}
+		err = testClient.Status().Update(ctx, rsip)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	objDef := fmt.Sprintf(`
+apiVersion: fluxcd.controlplane.io/v1
+kind: ResourceSet
+metadata:
+  name: previews
+  namespace: "%[1]s"
+spec:
+  inputStrategy:
+    name: Permute
+  inputsFrom:
+    - kind: ResourceSetInputProvider
+      name: pr
+    - kind: ResourceSetInputProvider
+      name: region
+  resources:
+    - apiVersion: v1
+      kind: ConfigMap
+      metadata:
+        name: pr-<< inputs.pr.id >>-<< inputs.region.id >>
+        namespace: "%[1]s"
+      data:
+        id: << inputs.id | quote >>
+`, ns.Name)
+
+	obj := &fluxcdv1.ResourceSet{}
+	err = yaml.Unmarshal([]byte(objDef), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Initialize the instance.
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	// Reconcile with the permuted inputs.
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	result := &fluxcdv1.ResourceSet{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.IsReady(result)).To(BeTrue())
+	g.Expect(result.Status.Inventory.Entries).To(HaveLen(6))
+
+	ids := make(map[string]bool)
+	for _, pr := range []string{"1", "2"} {
+		for _, region := range []string{"eu", "us", "ap"} {
+			cm := &corev1.ConfigMap{}
+			err = testClient.Get(ctx, client.ObjectKey{
+				Name:      fmt.Sprintf("pr-%s-%s", pr, region),
+				Namespace: ns.Name,
+			}, cm)
+			g.Expect(err).ToNot(HaveOccurred())
+			ids[cm.Data["id"]] = true
+		}
+	}
+	g.Expect(ids).To(HaveLen(6))
+}
+
+func TestResourceSetReconciler_InputStrategyPermuteKeys(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getResourceSetReconciler(t)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	upstreamNS, err := testEnv.CreateNamespace(ctx, "upstream")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Create the input providers with the same name in both namespaces.
+	for namespace, id := range map[string]string{
+		ns.Name:         "1",
+		upstreamNS.Name: "2",
+	} {
+		rsip := &fluxcdv1.ResourceSetInputProvider{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pr",
+				Namespace: namespace,
+			},
+			Spec: fluxcdv1.ResourceSetInputProviderSpec{
+				Type: fluxcdv1.InputProviderGitHubBranch,
+				URL:  "https://github.com/org/" + namespace,
+			},
+		}
+		err = testClient.Create(ctx, rsip)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		rsip.Status.ExportedInputs = []fluxcdv1.ResourceSetInput{{
+			"id": &apiextensionsv1.JSON{Raw: []byte(fmt.Sprintf(`"%s"`, id))},
+		}}
+		err = testClient.Status().Update(ctx, rsip)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	// The in-line inputs are nested under the ResourceSet name,
+	// the providers are nested under the keys set in inputsFrom.
+	objDef := fmt.Sprintf(`
+apiVersion: fluxcd.controlplane.io/v1
+kind: ResourceSet
+metadata:
+  name: pr
+  namespace: "%[1]s"
+spec:
+  inputStrategy:
+    name: Permute
+  inputs:
+    - id: "0"
+  inputsFrom:
+    - kind: ResourceSetInputProvider
+      name: pr
+      key: local_pr
+    - kind: ResourceSetInputProvider
+      name: pr
+      namespace: "%[2]s"
+      key: upstream_pr
+  resources:
+    - apiVersion: v1
+      kind: ConfigMap
+      metadata:
+        name: pr-<< inputs.pr.id >>-<< inputs.local_pr.id >>-<< inputs.upstream_pr.id >>
+        namespace: "%[1]s"
+`, ns.Name, upstreamNS.Name)
+
+	obj := &fluxcdv1.ResourceSet{}
+	err = yaml.Unmarshal([]byte(objDef), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Initialize the instance.
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	// Reconcile with the permuted inputs.
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	result := &fluxcdv1.ResourceSet{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.IsReady(result)).To(BeTrue())
+
+	cm := &corev1.ConfigMap{}
+	err = testClient.Get(ctx, client.ObjectKey{Name: "pr-0-1-2", Namespace: ns.Name}, cm)
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
 func TestResourceSetReconciler_Impersonation(t *testing.T) {
 	g := NewWithT(t)
 	reconciler := getResourceSetReconciler(t)
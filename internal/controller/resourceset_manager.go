@@ -6,7 +6,10 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -21,6 +24,16 @@ import (
 	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
 )
 
+const (
+	// inputsProviderIndexKey indexes the ResourceSets by
+	// the namespace/name of the input providers referenced by name.
+	inputsProviderIndexKey string = ".metadata.inputsProvider"
+
+	// inputsProviderSelectorIndexKey indexes the ResourceSets by
+	// the namespace of the input providers referenced by label selector.
+	inputsProviderSelectorIndexKey string = ".metadata.inputsProviderSelector"
+)
+
 // ResourceSetReconcilerOptions contains options for the reconciler.
 type ResourceSetReconcilerOptions struct {
 	RateLimiter workqueue.TypedRateLimiter[reconcile.Request]
@@ -28,13 +41,16 @@ type ResourceSetReconcilerOptions struct {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ResourceSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opts ResourceSetReconcilerOptions) error {
-	const inputsProviderIndexKey string = ".metadata.inputsProvider"
-
 	if err := mgr.GetCache().IndexField(ctx, &fluxcdv1.ResourceSet{}, inputsProviderIndexKey,
 		r.indexBy(fluxcdv1.ResourceSetInputProviderKind)); err != nil {
 		return fmt.Errorf("failed setting index fields: %w", err)
 	}
 
+	if err := mgr.GetCache().IndexField(ctx, &fluxcdv1.ResourceSet{}, inputsProviderSelectorIndexKey,
+		r.indexBySelector(fluxcdv1.ResourceSetInputProviderKind)); err != nil {
+		return fmt.Errorf("failed setting index fields: %w", err)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&fluxcdv1.ResourceSet{},
 			builder.WithPredicates(
@@ -45,7 +61,7 @@ func (r *ResourceSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.M
 			)).
 		Watches(
 			&fluxcdv1.ResourceSetInputProvider{},
-			handler.EnqueueRequestsFromMapFunc(r.requestsForChangeOf(inputsProviderIndexKey)),
+			handler.EnqueueRequestsFromMapFunc(r.requestsForChangeOf(inputsProviderIndexKey, inputsProviderSelectorIndexKey)),
 			builder.WithPredicates(exportedInputsChangePredicate),
 		).
 		WithOptions(controller.Options{
@@ -53,7 +69,7 @@ func (r *ResourceSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.M
 		}).Complete(r)
 }
 
-func (r *ResourceSetReconciler) requestsForChangeOf(indexKey string) handler.MapFunc {
+func (r *ResourceSetReconciler) requestsForChangeOf(indexKey, selectorIndexKey string) handler.MapFunc {
 	return func(ctx context.Context, obj client.Object) []reconcile.Request {
 		log := ctrl.LoggerFrom(ctx)
 
@@ -65,15 +81,70 @@ func (r *ResourceSetReconciler) requestsForChangeOf(indexKey string) handler.Map
 			return nil
 		}
 
-		reqs := make([]reconcile.Request, len(list.Items))
-		for i, rset := range list.Items {
-			reqs[i].NamespacedName = types.NamespacedName{Name: rset.Name, Namespace: rset.Namespace}
+		var selectorList fluxcdv1.ResourceSetList
+		if err := r.List(ctx, &selectorList, client.MatchingFields{
+			selectorIndexKey: obj.GetNamespace(),
+		}); err != nil {
+			log.Error(err, "failed to list objects for provider change")
+			return nil
+		}
+
+		seen := make(map[types.NamespacedName]bool)
+		reqs := make([]reconcile.Request, 0, len(list.Items))
+		enqueue := func(rset fluxcdv1.ResourceSet) {
+			key := types.NamespacedName{Name: rset.Name, Namespace: rset.Namespace}
+			if seen[key] {
+				return
+			}
+			seen[key] = true
+			reqs = append(reqs, reconcile.Request{NamespacedName: key})
+		}
+
+		for _, rset := range list.Items {
+			enqueue(rset)
+		}
+
+		for _, rset := range selectorList.Items {
+			if selectsInputProvider(&rset, obj) {
+				enqueue(rset)
+			}
 		}
 
 		return reqs
 	}
 }
 
+// selectsInputProvider returns true if any of the ResourceSet label
+// selectors matches the labels of the given input provider.
+func selectsInputProvider(rs *fluxcdv1.ResourceSet, obj client.Object) bool {
+	for _, ref := range rs.Spec.InputsFrom {
+		if ref.Selector == nil || ref.Kind != fluxcdv1.ResourceSetInputProviderKind ||
+			inputProviderNamespace(rs, ref) != obj.GetNamespace() {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
+		if err != nil {
+			continue
+		}
+
+		if selector.Matches(labels.Set(obj.GetLabels())) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inputProviderNamespace returns the namespace of the input provider
+// reference, defaulting to the namespace of the ResourceSet.
+func inputProviderNamespace(rs *fluxcdv1.ResourceSet, ref fluxcdv1.InputProviderReference) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+	return rs.GetNamespace()
+}
+
 func (r *ResourceSetReconciler) indexBy(kind string) func(o client.Object) []string {
 	return func(o client.Object) []string {
 		rs, ok := o.(*fluxcdv1.ResourceSet)
@@ -87,8 +158,8 @@ func (r *ResourceSetReconciler) indexBy(kind string) func(o client.Object) []str
 
 		results := make([]string, 0)
 		for _, k := range rs.Spec.InputsFrom {
-			if k.Kind == kind {
-				ns := rs.GetNamespace()
+			if k.Kind == kind && k.Name != "" {
+				ns := inputProviderNamespace(rs, k)
 				results = append(results, fmt.Sprintf("%s/%s", ns, k.Name))
 			}
 		}
@@ -97,12 +168,37 @@ func (r *ResourceSetReconciler) indexBy(kind string) func(o client.Object) []str
 	}
 }
 
+func (r *ResourceSetReconciler) indexBySelector(kind string) func(o client.Object) []string {
+	return func(o client.Object) []string {
+		rs, ok := o.(*fluxcdv1.ResourceSet)
+		if !ok {
+			return nil
+		}
+
+		if len(rs.Spec.InputsFrom) == 0 {
+			return nil
+		}
+
+		results := make([]string, 0)
+		for _, k := range rs.Spec.InputsFrom {
+			if k.Kind == kind && k.Selector != nil {
+				results = append(results, inputProviderNamespace(rs, k))
+			}
+		}
+
+		return results
+	}
+}
+
 var exportedInputsChangePredicate = predicate.Funcs{
 	UpdateFunc: func(e event.UpdateEvent) bool {
 		oldObj := e.ObjectOld.(*fluxcdv1.ResourceSetInputProvider)
 		newObj := e.ObjectNew.(*fluxcdv1.ResourceSetInputProvider)
 
-		// Trigger reconciliation only if the exported inputs have changed.
-		return oldObj.Status.LastExportedRevision != newObj.Status.LastExportedRevision
+		// Trigger reconciliation only if the exported inputs have changed,
+		// or if the labels have changed as the provider may be matched
+		// by a ResourceSet label selector.
+		return oldObj.Status.LastExportedRevision != newObj.Status.LastExportedRevision ||
+			!maps.Equal(oldObj.GetLabels(), newObj.GetLabels())
 	},
 }
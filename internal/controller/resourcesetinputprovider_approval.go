@@ -0,0 +1,198 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/gitprovider"
+)
+
+// approveSHARegex matches the commit SHA prefixes accepted in the approve
+// annotation, a minimum length avoids approving unrelated commits.
+var approveSHARegex = regexp.MustCompile(`^[0-9a-f]{7,64}$`)
+
+// inputsApproval holds the ids of the input sets that were approved
+// or that started waiting for approval in the current reconciliation.
+type inputsApproval struct {
+	approved []string
+	pending  []string
+}
+
+// approveInputs returns the approved input sets and holds the rest in the
+// object status pending inputs. An input set stays approved until its head
+// commit changes, if re-approval on new commits is required, in which case
+// the time when each head commit was first seen is recorded in the object
+// status, as the commit dates are set by the commit author. The reader is
+// used for the label and review approvals, and can be nil when the provider
+// doesn't support them.
+func approveInputs(ctx context.Context,
+	obj *fluxcdv1.ResourceSetInputProvider,
+	reader gitprovider.ApprovalReader,
+	inputs []fluxcdv1.ResourceSetInput,
+	now time.Time) ([]fluxcdv1.ResourceSetInput, inputsApproval, error) {
+	var result inputsApproval
+	spec := obj.Spec.Approval
+	if spec == nil {
+		obj.Status.PendingInputs = nil
+		obj.Status.ApprovedInputs = nil
+		obj.Status.InputCommits = nil
+		return inputs, result, nil
+	}
+
+	previous := make(map[string]fluxcdv1.InputApproval, len(obj.Status.ApprovedInputs))
+	for _, a := range obj.Status.ApprovedInputs {
+		previous[a.ID] = a
+	}
+	previousCommits := make(map[string]fluxcdv1.InputCommit, len(obj.Status.InputCommits))
+	for _, c := range obj.Status.InputCommits {
+		previousCommits[c.ID] = c
+	}
+	previousPending := indexInputsByID(obj.Status.PendingInputs)
+	annotated := parseApproveAnnotation(obj.GetAnnotations()[fluxcdv1.ApproveAnnotation])
+
+	approved := make([]fluxcdv1.ResourceSetInput, 0, len(inputs))
+	approvals := make([]fluxcdv1.InputApproval, 0, len(inputs))
+	var pending []fluxcdv1.ResourceSetInput
+	var commits []fluxcdv1.InputCommit
+	for _, input := range inputs {
+		id, ok := inputID(input)
+		if !ok {
+			pending = append(pending, input)
+			continue
+		}
+		sha := inputString(input, "sha")
+
+		var firstSeen time.Time
+		if spec.ReapproveOnNewCommit && sha != "" {
+			c := fluxcdv1.InputCommit{ID: id, SHA: sha, FirstSeen: metav1.NewTime(now.Truncate(time.Second))}
+			if prev, ok := previousCommits[id]; ok && prev.SHA == sha {
+				c.FirstSeen = prev.FirstSeen
+			}
+			commits = append(commits, c)
+			firstSeen = c.FirstSeen.Time
+		}
+
+		if a, ok := previous[id]; ok && (!spec.ReapproveOnNewCommit || a.SHA == sha) {
+			approvals = append(approvals, a)
+			approved = append(approved, input)
+			continue
+		}
+
+		a, err := findApproval(ctx, spec, reader, annotated[id], input, sha, firstSeen)
+		if err != nil {
+			return nil, result, err
+		}
+		if a != nil {
+			a.ID = id
+			a.ApprovedAt = metav1.NewTime(now)
+			approvals = append(approvals, *a)
+			approved = append(approved, input)
+			by := strings.ToLower(a.Method)
+			if a.ApprovedBy != "" {
+				by = a.ApprovedBy
+			}
+			result.approved = append(result.approved, fmt.Sprintf("%s by %s", id, by))
+			continue
+		}
+
+		pending = append(pending, input)
+		if _, ok := previousPending[id]; !ok {
+			result.pending = append(result.pending, id)
+		}
+	}
+
+	obj.Status.PendingInputs = pending
+	obj.Status.ApprovedInputs = approvals
+	obj.Status.InputCommits = commits
+	return approved, result, nil
+}
+
+// findApproval returns the approval of the input set by annotation, label
+// or review, in this order, or nil if the input set is not approved.
+// When re-approval on new commits is required, the annotation must name the
+// head commit SHA, the label must be applied after the head commit was first
+// seen and the review must be for the head commit.
+func findApproval(ctx context.Context,
+	spec *fluxcdv1.ResourceSetInputApproval,
+	reader gitprovider.ApprovalReader,
+	annotatedSHAs []string,
+	input fluxcdv1.ResourceSetInput,
+	sha string,
+	firstSeen time.Time) (*fluxcdv1.InputApproval, error) {
+	for _, s := range annotatedSHAs {
+		if (s == "" && (!spec.ReapproveOnNewCommit || sha == "")) || (s != "" && strings.HasPrefix(strings.ToLower(sha), s)) {
+			return &fluxcdv1.InputApproval{SHA: sha, Method: fluxcdv1.ApprovalMethodAnnotation}, nil
+		}
+	}
+
+	var number int
+	if v, ok := input["number"]; ok && v != nil {
+		_ = json.Unmarshal(v.Raw, &number)
+	}
+	if reader == nil || number == 0 {
+		return nil, nil
+	}
+
+	if spec.Label != "" && slices.Contains(inputLabels(input), spec.Label) {
+		user, labeledAt, err := reader.LabeledBy(ctx, number, spec.Label)
+		if err != nil {
+			return nil, err
+		}
+		if user != "" && slices.Contains(spec.Approvers, user) {
+			if !spec.ReapproveOnNewCommit || labeledAt.After(firstSeen) {
+				return &fluxcdv1.InputApproval{SHA: sha, Method: fluxcdv1.ApprovalMethodLabel, ApprovedBy: user}, nil
+			}
+		}
+	}
+
+	if spec.Review {
+		var reviewedSHA string
+		if spec.ReapproveOnNewCommit {
+			reviewedSHA = sha
+		}
+		users, err := reader.ListApprovers(ctx, number, reviewedSHA)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if slices.Contains(spec.Approvers, user) {
+				return &fluxcdv1.InputApproval{SHA: sha, Method: fluxcdv1.ApprovalMethodReview, ApprovedBy: user}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// parseApproveAnnotation parses the comma-separated list of '<id>' and
+// '<id>@<sha>' entries, and returns the approved SHA prefixes indexed by id.
+// The '<id>' entries approve any commit and are recorded as an empty SHA.
+// The entries with a SHA prefix shorter than 7 hex characters are ignored.
+func parseApproveAnnotation(value string) map[string][]string {
+	result := make(map[string][]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, sha, found := strings.Cut(entry, "@")
+		id = strings.TrimSpace(id)
+		sha = strings.ToLower(strings.TrimSpace(sha))
+		if found && !approveSHARegex.MatchString(sha) {
+			continue
+		}
+		result[id] = append(result[id], sha)
+	}
+	return result
+}
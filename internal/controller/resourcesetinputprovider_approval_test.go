@@ -0,0 +1,206 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+type fakeApprovalReader struct {
+	labeledBy map[int]string
+	labeledAt time.Time
+	approvers map[int][]string
+}
+
+func (f *fakeApprovalReader) LabeledBy(_ context.Context, number int, _ string) (string, time.Time, error) {
+	return f.labeledBy[number], f.labeledAt, nil
+}
+
+func (f *fakeApprovalReader) ListApprovers(_ context.Context, number int, _ string) ([]string, error) {
+	return f.approvers[number], nil
+}
+
+func TestApproveInputs(t *testing.T) {
+	now := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+	pr := func(number, sha string, labels string) fluxcdv1.ResourceSetInput {
+		in := fluxcdv1.ResourceSetInput{
+			"id":              &apiextensionsv1.JSON{Raw: []byte(`"` + number + `"`)},
+			"number":          &apiextensionsv1.JSON{Raw: []byte(number)},
+			"sha":             &apiextensionsv1.JSON{Raw: []byte(`"` + sha + `"`)},
+			"commitTimestamp": &apiextensionsv1.JSON{Raw: []byte(`"2025-04-09T12:00:00Z"`)},
+		}
+		if labels != "" {
+			in["labels"] = &apiextensionsv1.JSON{Raw: []byte(labels)}
+		}
+		return in
+	}
+	reader := &fakeApprovalReader{
+		labeledBy: map[int]string{1: "alice", 2: "mallory"},
+		labeledAt: now.Add(-time.Hour),
+		approvers: map[int][]string{3: {"mallory", "bob"}, 4: {"mallory"}},
+	}
+
+	tests := []struct {
+		name       string
+		approval   *fluxcdv1.ResourceSetInputApproval
+		annotation string
+		previous   []fluxcdv1.InputApproval
+		commits    []fluxcdv1.InputCommit
+		pending    []fluxcdv1.ResourceSetInput
+		inputs     []fluxcdv1.ResourceSetInput
+		exported   []string
+		approved   []string
+		waiting    []string
+	}{
+		{
+			name:     "no approval",
+			inputs:   []fluxcdv1.ResourceSetInput{pr("1", "a1", "")},
+			exported: []string{"1"},
+		},
+		{
+			name: "label and review from approvers",
+			approval: &fluxcdv1.ResourceSetInputApproval{
+				Label:     "deploy",
+				Review:    true,
+				Approvers: []string{"alice", "bob"},
+			},
+			inputs: []fluxcdv1.ResourceSetInput{
+				pr("1", "a1", `["deploy"]`),
+				pr("2", "a2", `["deploy"]`),
+				pr("3", "a3", ""),
+				pr("4", "a4", ""),
+			},
+			exported: []string{"1", "3"},
+			approved: []string{"1 by alice", "3 by bob"},
+			waiting:  []string{"2", "4"},
+		},
+		{
+			name:       "annotation",
+			approval:   &fluxcdv1.ResourceSetInputApproval{},
+			annotation: "1, 2@A2C4E6F, 3@b3c5d7e, 4@a4",
+			inputs: []fluxcdv1.ResourceSetInput{
+				pr("1", "a1", ""),
+				pr("2", "a2c4e6f8", ""),
+				pr("3", "a3c5d7e9", ""),
+				pr("4", "a4b5c6d7", ""),
+			},
+			exported: []string{"1", "2"},
+			approved: []string{"1 by annotation", "2 by annotation"},
+			waiting:  []string{"3", "4"},
+		},
+		{
+			name:     "previous approvals",
+			approval: &fluxcdv1.ResourceSetInputApproval{},
+			previous: []fluxcdv1.InputApproval{
+				{ID: "1", SHA: "a1", Method: fluxcdv1.ApprovalMethodAnnotation},
+				{ID: "2", SHA: "old", Method: fluxcdv1.ApprovalMethodAnnotation},
+			},
+			pending:  []fluxcdv1.ResourceSetInput{pr("3", "a3", "")},
+			inputs:   []fluxcdv1.ResourceSetInput{pr("1", "a1", ""), pr("2", "a2", ""), pr("3", "a3", "")},
+			exported: []string{"1", "2"},
+		},
+		{
+			name:       "new commit requires re-approval",
+			approval:   &fluxcdv1.ResourceSetInputApproval{ReapproveOnNewCommit: true},
+			annotation: "2",
+			previous: []fluxcdv1.InputApproval{
+				{ID: "1", SHA: "a1", Method: fluxcdv1.ApprovalMethodAnnotation},
+				{ID: "2", SHA: "old", Method: fluxcdv1.ApprovalMethodAnnotation},
+			},
+			inputs:   []fluxcdv1.ResourceSetInput{pr("1", "a1", ""), pr("2", "a2", "")},
+			exported: []string{"1"},
+			waiting:  []string{"2"},
+		},
+		{
+			name: "label applied before the head commit was first seen",
+			approval: &fluxcdv1.ResourceSetInputApproval{
+				Label:                "deploy",
+				Approvers:            []string{"alice"},
+				ReapproveOnNewCommit: true,
+			},
+			previous: []fluxcdv1.InputApproval{
+				{ID: "1", SHA: "old", Method: fluxcdv1.ApprovalMethodLabel, ApprovedBy: "alice"},
+			},
+			commits: []fluxcdv1.InputCommit{
+				{ID: "1", SHA: "old", FirstSeen: metav1.NewTime(now.Add(-2 * time.Hour))},
+			},
+			// The backdated commit date is ignored.
+			inputs: []fluxcdv1.ResourceSetInput{func() fluxcdv1.ResourceSetInput {
+				in := pr("1", "a1", `["deploy"]`)
+				in["commitTimestamp"] = &apiextensionsv1.JSON{Raw: []byte(`"2020-01-01T00:00:00Z"`)}
+				return in
+			}()},
+			waiting: []string{"1"},
+		},
+		{
+			name: "label applied after the head commit was first seen",
+			approval: &fluxcdv1.ResourceSetInputApproval{
+				Label:                "deploy",
+				Approvers:            []string{"alice"},
+				ReapproveOnNewCommit: true,
+			},
+			commits: []fluxcdv1.InputCommit{
+				{ID: "1", SHA: "a1", FirstSeen: metav1.NewTime(now.Add(-2 * time.Hour))},
+			},
+			inputs:   []fluxcdv1.ResourceSetInput{pr("1", "a1", `["deploy"]`)},
+			exported: []string{"1"},
+			approved: []string{"1 by alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &fluxcdv1.ResourceSetInputProvider{}
+			obj.Spec.Approval = tt.approval
+			obj.Status.ApprovedInputs = tt.previous
+			obj.Status.PendingInputs = tt.pending
+			obj.Status.InputCommits = tt.commits
+			if tt.annotation != "" {
+				obj.SetAnnotations(map[string]string{fluxcdv1.ApproveAnnotation: tt.annotation})
+			}
+
+			exported, result, err := approveInputs(context.Background(), obj, reader, tt.inputs, now)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var exportedIDs []string
+			for _, in := range exported {
+				id, _ := inputID(in)
+				exportedIDs = append(exportedIDs, id)
+			}
+			g.Expect(exportedIDs).To(Equal(tt.exported))
+			g.Expect(result.approved).To(ConsistOf(tt.approved))
+			g.Expect(result.pending).To(ConsistOf(tt.waiting))
+
+			if tt.approval == nil {
+				g.Expect(obj.Status.ApprovedInputs).To(BeEmpty())
+				g.Expect(obj.Status.PendingInputs).To(BeEmpty())
+				g.Expect(obj.Status.InputCommits).To(BeEmpty())
+				return
+			}
+
+			g.Expect(obj.Status.ApprovedInputs).To(HaveLen(len(tt.exported)))
+			g.Expect(obj.Status.PendingInputs).To(HaveLen(len(tt.inputs) - len(tt.exported)))
+			if tt.approval.ReapproveOnNewCommit {
+				g.Expect(obj.Status.InputCommits).To(HaveLen(len(tt.inputs)))
+			} else {
+				g.Expect(obj.Status.InputCommits).To(BeEmpty())
+			}
+			if len(tt.previous) == 0 {
+				for _, a := range obj.Status.ApprovedInputs {
+					g.Expect(a.ApprovedAt).To(Equal(metav1.NewTime(now)))
+				}
+			}
+		})
+	}
+}
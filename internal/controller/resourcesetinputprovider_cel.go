@@ -0,0 +1,128 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/fluxcd/pkg/runtime/cel"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/gitprovider"
+)
+
+// filterResultsByExpr returns the results for which the
+// filter CEL expression evaluates to true.
+func filterResultsByExpr(ctx context.Context,
+	expr string,
+	results []gitprovider.Result) ([]gitprovider.Result, error) {
+	celExpr, err := cel.NewExpression(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", err)
+	}
+
+	filtered := make([]gitprovider.Result, 0, len(results))
+	for _, result := range results {
+		ok, err := celExpr.EvaluateBoolean(ctx, resultVariables(result))
+		if err != nil {
+			return nil, fmt.Errorf("filter expression failed for result %s: %w", result.ID, err)
+		}
+		if ok {
+			filtered = append(filtered, result)
+		}
+	}
+
+	return filtered, nil
+}
+
+// resultVariables returns the result fields as CEL variables,
+// including the fields that are not set, so that the expressions
+// don't fail for the results with empty values.
+func resultVariables(r gitprovider.Result) map[string]any {
+	labels := r.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	topics := r.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+
+	return map[string]any{
+		"id":              r.ID,
+		"sha":             r.SHA,
+		"branch":          r.Branch,
+		"tag":             r.Tag,
+		"version":         r.Version,
+		"digest":          r.Digest,
+		"author":          r.Author,
+		"title":           r.Title,
+		"labels":          labels,
+		"number":          r.Number,
+		"url":             r.URL,
+		"baseBranch":      r.BaseBranch,
+		"headRepository":  r.HeadRepository,
+		"commitTimestamp": r.CommitTimestamp,
+		"draft":           r.Draft,
+		"createdAt":       r.CreatedAt,
+		"updatedAt":       r.UpdatedAt,
+		"repository":      r.Repository,
+		"repositoryPath":  r.RepositoryPath,
+		"cloneURL":        r.CloneURL,
+		"defaultBranch":   r.DefaultBranch,
+		"topics":          topics,
+	}
+}
+
+// transformInputs adds to each input set the values computed by
+// the transform CEL expressions. The expressions are evaluated
+// against the input set before the transformation.
+func transformInputs(ctx context.Context,
+	transform map[string]string,
+	inputs []fluxcdv1.ResourceSetInput) error {
+	if len(transform) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(transform))
+	exprs := make(map[string]*cel.Expression, len(transform))
+	for name, expr := range transform {
+		celExpr, err := cel.NewExpression(expr)
+		if err != nil {
+			return fmt.Errorf("invalid transform expression for '%s': %w", name, err)
+		}
+		names = append(names, name)
+		exprs[name] = celExpr
+	}
+	sort.Strings(names)
+
+	for i, input := range inputs {
+		vars := make(map[string]any, len(input))
+		for k, v := range input {
+			var data any
+			if err := json.Unmarshal(v.Raw, &data); err != nil {
+				return fmt.Errorf("failed to unmarshal inputs[%d]: %w", i, err)
+			}
+			vars[k] = data
+		}
+
+		for _, name := range names {
+			value, err := exprs[name].EvaluateString(ctx, vars)
+			if err != nil {
+				return fmt.Errorf("transform expression for '%s' failed for inputs[%d]: %w", name, i, err)
+			}
+			b, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("failed to marshal value to JSON %v: %w", value, err)
+			}
+			input[name] = &apiextensionsv1.JSON{Raw: b}
+		}
+	}
+
+	return nil
+}
@@ -8,26 +8,33 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"hash/adler32"
 	"regexp"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/cache"
 	"github.com/fluxcd/pkg/git/github"
 	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/patch"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/opencontainers/go-digest"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	kuberecorder "k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/yaml"
 
@@ -45,6 +52,17 @@ type ResourceSetInputProviderReconciler struct {
 	Scheme        *runtime.Scheme
 	StatusManager string
 	TokenCache    *cache.TokenCache
+
+	// selectorController and selectorCache are used to watch
+	// the kinds selected by the KubernetesSelector providers.
+	selectorController controller.Controller
+	selectorCache      ctrlcache.Cache
+	selectorKinds      map[schema.GroupVersionKind]bool
+	selectorMu         sync.Mutex
+
+	// sourceKinds holds the Flux source kinds watched
+	// for the artifacts read by the FluxArtifact providers.
+	sourceKinds map[schema.GroupVersionKind]bool
 }
 
 // +kubebuilder:rbac:groups=fluxcd.controlplane.io,resources=resourcesetinputproviders,verbs=get;list;watch;create;update;patch;delete
@@ -129,6 +147,57 @@ func (r *ResourceSetInputProviderReconciler) reconcile(ctx context.Context,
 		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
 	}
 
+	// Stall the reconciliation until the schedule is fixed.
+	if err := validateSchedule(obj.Spec.Schedule); err != nil {
+		msg := fmt.Sprintf("invalid schedule: %s", err.Error())
+		conditions.MarkFalse(obj,
+			meta.ReadyCondition,
+			fluxcdv1.InvalidScheduleReason,
+			"%s", msg)
+		conditions.MarkTrue(obj,
+			meta.StalledCondition,
+			fluxcdv1.InvalidScheduleReason,
+			"%s", msg)
+		log.Error(err, "invalid schedule")
+		r.notify(ctx, obj, corev1.EventTypeWarning, fluxcdv1.InvalidScheduleReason, msg)
+		return ctrl.Result{}, nil
+	}
+
+	// Export the inputs from the selected Kubernetes objects.
+	if obj.Spec.Type == fluxcdv1.InputProviderKubernetesSelector {
+		exportedInputs, err := r.selectObjects(ctx, obj)
+		if err != nil {
+			msg := fmt.Sprintf("failed to select objects %s", err.Error())
+			conditions.MarkFalse(obj,
+				meta.ReadyCondition,
+				meta.ReconciliationFailedReason,
+				"%s", msg)
+			r.notify(ctx, obj, corev1.EventTypeWarning, meta.ReconciliationFailedReason, msg)
+			return ctrl.Result{}, err
+		}
+
+		return r.exportInputs(ctx, obj, exportedInputs, "", reconcileStart)
+	}
+
+	// Export the inputs from the file in the Flux source artifact.
+	if obj.Spec.Type == fluxcdv1.InputProviderFluxArtifact {
+		artifactCtx, cancel := context.WithTimeout(ctx, obj.GetTimeout())
+		defer cancel()
+
+		exportedInputs, sourceRevision, err := r.readFluxArtifact(artifactCtx, obj)
+		if err != nil {
+			msg := fmt.Sprintf("failed to read artifact %s", err.Error())
+			conditions.MarkFalse(obj,
+				meta.ReadyCondition,
+				meta.ReconciliationFailedReason,
+				"%s", msg)
+			r.notify(ctx, obj, corev1.EventTypeWarning, meta.ReconciliationFailedReason, msg)
+			return ctrl.Result{}, err
+		}
+
+		return r.exportInputs(ctx, obj, exportedInputs, sourceRevision, reconcileStart)
+	}
+
 	// Get the auth data.
 	var authData map[string][]byte
 	if obj.Spec.SecretRef != nil {
@@ -161,10 +230,64 @@ func (r *ResourceSetInputProviderReconciler) reconcile(ctx context.Context,
 	providerCtx, cancel := context.WithTimeout(ctx, obj.GetTimeout())
 	defer cancel()
 
-	// Create the provider based on the object type.
-	provider, err := r.newGitProvider(providerCtx, obj, certPool, authData)
+	// Export the inputs from the HTTP JSON response.
+	if obj.Spec.Type == fluxcdv1.InputProviderHTTPJSON {
+		exportedInputs, err := r.callHTTPJSON(providerCtx, obj, certPool, authData)
+		if err != nil {
+			msg := fmt.Sprintf("failed to call provider %s", err.Error())
+			conditions.MarkFalse(obj,
+				meta.ReadyCondition,
+				meta.ReconciliationFailedReason,
+				"%s", msg)
+			r.notify(ctx, obj, corev1.EventTypeWarning, meta.ReconciliationFailedReason, msg)
+			return ctrl.Result{}, err
+		}
+
+		return r.exportInputs(ctx, obj, exportedInputs, "", reconcileStart)
+	}
+
+	// Create a provider for each URL based on the object type.
+	urls := obj.GetURLs()
+	providers := make([]gitprovider.Interface, 0, len(urls))
+	for _, url := range urls {
+		provider, err := r.newGitProvider(providerCtx, obj, url, certPool, authData)
+		if err != nil {
+			msg := fmt.Sprintf("failed to create provider %s", err.Error())
+			conditions.MarkFalse(obj,
+				meta.ReadyCondition,
+				meta.ReconciliationFailedReason,
+				"%s", msg)
+			r.notify(ctx, obj, corev1.EventTypeWarning, meta.ReconciliationFailedReason, msg)
+			return ctrl.Result{}, err
+		}
+		providers = append(providers, provider)
+	}
+	provider := providers[0]
+
+	// Get the inputs from the provider, or from the file in the Git repository.
+	var exportedInputs []fluxcdv1.ResourceSetInput
+	var sourceRevision string
+	if strings.HasSuffix(obj.Spec.Type, "File") {
+		exportedInputs, sourceRevision, err = r.readGitFile(providerCtx, obj, provider)
+	} else {
+		exportedInputs, err = r.callProviders(providerCtx, obj, providers)
+	}
+
+	// Report the API rate limit status and requeue at the
+	// retry time if the provider requests are rate limited.
+	rateLimit := r.recordRateLimit(obj, urls, providers)
+	if err != nil && rateLimit != nil && rateLimit.Exceeded() {
+		msg := fmt.Sprintf("API rate limit exceeded, retrying at %s",
+			rateLimit.RetryAt.UTC().Format(time.RFC3339))
+		conditions.MarkFalse(obj,
+			meta.ReadyCondition,
+			fluxcdv1.RateLimitExceededReason,
+			"%s", msg)
+		r.notify(ctx, obj, corev1.EventTypeWarning, fluxcdv1.RateLimitExceededReason, msg)
+		return ctrl.Result{RequeueAfter: time.Until(rateLimit.RetryAt)}, nil
+	}
 	if err != nil {
-		msg := fmt.Sprintf("failed to create provider %s", err.Error())
+		msg := fmt.Sprintf("failed to call provider %s", err.Error())
 		conditions.MarkFalse(obj,
 			meta.ReadyCondition,
 			meta.ReconciliationFailedReason,
@@ -173,10 +296,57 @@ func (r *ResourceSetInputProviderReconciler) reconcile(ctx context.Context,
 		return ctrl.Result{}, err
 	}
 
-	// Get the provider options.
-	exportedInputs, err := r.callProvider(providerCtx, obj, provider)
+	// Hold the input sets waiting for approval.
+	reader, _ := provider.(gitprovider.ApprovalReader)
+	exportedInputs, approval, err := approveInputs(providerCtx, obj, reader, exportedInputs, time.Now())
 	if err != nil {
-		msg := fmt.Sprintf("failed to call provider %s", err.Error())
+		msg := fmt.Sprintf("failed to check approvals %s", err.Error())
+		conditions.MarkFalse(obj,
+			meta.ReadyCondition,
+			meta.ReconciliationFailedReason,
+			"%s", msg)
+		r.notify(ctx, obj, corev1.EventTypeWarning, meta.ReconciliationFailedReason, msg)
+		return ctrl.Result{}, err
+	}
+	if len(approval.approved) > 0 {
+		msg := fmt.Sprintf("Input sets approved: [%s]", strings.Join(approval.approved, ", "))
+		log.Info(msg)
+		r.notify(ctx, obj, corev1.EventTypeNormal, fluxcdv1.InputsApprovedReason, msg)
+	}
+	if len(approval.pending) > 0 {
+		msg := fmt.Sprintf("Input sets waiting for approval: [%s]", strings.Join(approval.pending, ", "))
+		log.Info(msg)
+		r.notify(ctx, obj, corev1.EventTypeNormal, fluxcdv1.InputsPendingApprovalReason, msg)
+	}
+
+	return r.exportInputs(ctx, obj, exportedInputs, sourceRevision, reconcileStart)
+}
+
+// exportInputs updates the object status with the exported inputs
+// and the revision of the source they were read from, if any,
+// and marks the object as ready.
+func (r *ResourceSetInputProviderReconciler) exportInputs(ctx context.Context,
+	obj *fluxcdv1.ResourceSetInputProvider,
+	exportedInputs []fluxcdv1.ResourceSetInput,
+	sourceRevision string,
+	reconcileStart time.Time) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Add the inputs computed by the transform expressions.
+	if err := transformInputs(ctx, obj.Spec.Transform, exportedInputs); err != nil {
+		msg := fmt.Sprintf("failed to transform inputs %s", err.Error())
+		conditions.MarkFalse(obj,
+			meta.ReadyCondition,
+			meta.ReconciliationFailedReason,
+			"%s", msg)
+		r.notify(ctx, obj, corev1.EventTypeWarning, meta.ReconciliationFailedReason, msg)
+		return ctrl.Result{}, err
+	}
+
+	// Check the schedule windows in which the exported inputs can change.
+	open, nextWindow, err := checkSchedule(obj.Spec.Schedule, time.Now())
+	if err != nil {
+		msg := fmt.Sprintf("failed to check schedule %s", err.Error())
 		conditions.MarkFalse(obj,
 			meta.ReadyCondition,
 			meta.ReconciliationFailedReason,
@@ -185,7 +355,26 @@ func (r *ResourceSetInputProviderReconciler) reconcile(ctx context.Context,
 		return ctrl.Result{}, err
 	}
 
-	// Update the object status with the exported inputs.
+	// Drop the expired input sets and report the expiring ones. While the
+	// exported inputs are frozen, the expiry is evaluated on a copy of the
+	// object to compute the pending revision, without recording the activity
+	// of the input sets or reporting them as expired.
+	expiryObj := obj
+	if !open {
+		expiryObj = obj.DeepCopy()
+	}
+	exportedInputs, expiry, err := expireInputs(expiryObj, exportedInputs, time.Now())
+	if err != nil {
+		msg := fmt.Sprintf("failed to expire inputs %s", err.Error())
+		conditions.MarkFalse(obj,
+			meta.ReadyCondition,
+			meta.ReconciliationFailedReason,
+			"%s", msg)
+		r.notify(ctx, obj, corev1.EventTypeWarning, meta.ReconciliationFailedReason, msg)
+		return ctrl.Result{}, err
+	}
+
+	// Compute the revision of the exported inputs.
 	data, err := yaml.Marshal(exportedInputs)
 	if err != nil {
 		msg := fmt.Sprintf("failed to marshal exported inputs %s", err.Error())
@@ -196,11 +385,44 @@ func (r *ResourceSetInputProviderReconciler) reconcile(ctx context.Context,
 		r.notify(ctx, obj, corev1.EventTypeWarning, meta.ReconciliationFailedReason, msg)
 		return ctrl.Result{}, err
 	}
+	revision := digest.FromBytes(data).String()
+
+	// Retain the last exported inputs outside the schedule windows.
+	if !open {
+		return r.freezeInputs(ctx, obj, revision, nextWindow, reconcileStart), nil
+	}
+
+	if len(expiry.expiring) > 0 {
+		msg := fmt.Sprintf("Input sets expiring soon: [%s]", strings.Join(expiry.expiring, ", "))
+		log.Info(msg)
+		r.notify(ctx, obj, corev1.EventTypeWarning, fluxcdv1.InputsExpiringReason, msg)
+	}
+	if len(expiry.expired) > 0 {
+		msg := fmt.Sprintf("Input sets expired: [%s]", strings.Join(expiry.expired, ", "))
+		log.Info(msg)
+		r.notify(ctx, obj, corev1.EventTypeNormal, fluxcdv1.InputsExpiredReason, msg)
+	}
+
+	conditions.Delete(obj, fluxcdv1.FrozenCondition)
+	obj.Status.PendingRevision = ""
+
+	// Record the changes of the exported inputs.
+	if revision != obj.Status.LastExportedRevision {
+		diff := diffInputs(obj.Status.ExportedInputs, exportedInputs)
+		recordExportHistory(obj, revision, diff, time.Now())
+		if !diff.isEmpty() {
+			msg := fmt.Sprintf("Exported inputs changed: %s", diff.String())
+			log.Info(msg)
+			r.notify(ctx, obj, corev1.EventTypeNormal, fluxcdv1.InputsChangedReason, msg)
+		}
+	}
+
 	obj.Status.ExportedInputs = exportedInputs
-	obj.Status.LastExportedRevision = digest.FromBytes(data).String()
+	obj.Status.LastExportedRevision = revision
+	obj.Status.SourceRevision = sourceRevision
 
 	// Mark the object as ready and set the last applied revision.
-	msg = fmt.Sprintf("Reconciliation finished in %s", fmtDuration(reconcileStart))
+	msg := fmt.Sprintf("Reconciliation finished in %s", fmtDuration(reconcileStart))
 	conditions.MarkTrue(obj,
 		meta.ReadyCondition,
 		meta.ReconciliationSucceededReason,
@@ -211,34 +433,138 @@ func (r *ResourceSetInputProviderReconciler) reconcile(ctx context.Context,
 		meta.ReconciliationSucceededReason,
 		msg)
 
-	return requeueAfterResourceSetInputProvider(obj), nil
+	// Requeue at the next expiry warning or expiry time.
+	result := requeueAfterResourceSetInputProvider(obj)
+	if !expiry.next.IsZero() {
+		if untilNext := time.Until(expiry.next); result.RequeueAfter == 0 || untilNext < result.RequeueAfter {
+			result.RequeueAfter = untilNext
+		}
+	}
+	return result, nil
 }
 
-// newGitProvider returns a new Git provider based on the type specified in the ResourceSetInputProvider object.
+// freezeInputs retains the last exported inputs until the next schedule
+// window opens, and records the revision of the inputs waiting to be exported.
+func (r *ResourceSetInputProviderReconciler) freezeInputs(ctx context.Context,
+	obj *fluxcdv1.ResourceSetInputProvider,
+	revision string,
+	nextWindow time.Time,
+	reconcileStart time.Time) ctrl.Result {
+	log := ctrl.LoggerFrom(ctx)
+
+	frozenMsg := "Exported inputs are frozen outside the schedule windows"
+	if !nextWindow.IsZero() {
+		frozenMsg = fmt.Sprintf("Exported inputs are frozen until %s", nextWindow.UTC().Format(time.RFC3339))
+	}
+
+	obj.Status.PendingRevision = ""
+	if revision != obj.Status.LastExportedRevision {
+		obj.Status.PendingRevision = revision
+		log.Info(frozenMsg, "pendingRevision", revision)
+	}
+
+	conditions.MarkTrue(obj,
+		fluxcdv1.FrozenCondition,
+		fluxcdv1.OutsideScheduleReason,
+		"%s", frozenMsg)
+
+	msg := fmt.Sprintf("Reconciliation finished in %s", fmtDuration(reconcileStart))
+	conditions.MarkTrue(obj,
+		meta.ReadyCondition,
+		meta.ReconciliationSucceededReason,
+		"%s", msg)
+	log.Info(msg)
+
+	// Requeue when the next window opens to export the pending inputs.
+	result := requeueAfterResourceSetInputProvider(obj)
+	if !nextWindow.IsZero() {
+		if untilNext := time.Until(nextWindow); result.RequeueAfter == 0 || untilNext < result.RequeueAfter {
+			result.RequeueAfter = untilNext
+		}
+	}
+	return result
+}
+
+// newGitProvider returns a new Git provider for the given URL
+// based on the type specified in the ResourceSetInputProvider object.
 func (r *ResourceSetInputProviderReconciler) newGitProvider(ctx context.Context,
 	obj *fluxcdv1.ResourceSetInputProvider,
+	url string,
 	certPool *x509.CertPool,
 	authData map[string][]byte) (gitprovider.Interface, error) {
 	switch {
 	case strings.HasPrefix(obj.Spec.Type, "GitHub"):
-		token, err := r.getGitHubToken(ctx, obj, authData)
+		token, err := r.getGitHubToken(ctx, obj, url, authData)
 		if err != nil {
 			return nil, err
 		}
-		return gitprovider.NewGitHubProvider(ctx, gitprovider.Options{
-			URL:      obj.Spec.URL,
+		opts := gitprovider.Options{
+			URL:      url,
 			CertPool: certPool,
 			Token:    token,
-		})
+		}
+		if obj.Spec.Type == fluxcdv1.InputProviderGitHubOrganization {
+			return gitprovider.NewGitHubOrganizationProvider(ctx, opts)
+		}
+		return gitprovider.NewGitHubProvider(ctx, opts)
 	case strings.HasPrefix(obj.Spec.Type, "GitLab"):
 		token, err := r.getGitLabToken(obj, authData)
 		if err != nil {
 			return nil, err
 		}
-		return gitprovider.NewGitLabProvider(ctx, gitprovider.Options{
-			URL:      obj.Spec.URL,
+		opts := gitprovider.Options{
+			URL:      url,
 			CertPool: certPool,
 			Token:    token,
+		}
+		if obj.Spec.Type == fluxcdv1.InputProviderGitLabGroup {
+			return gitprovider.NewGitLabGroupProvider(ctx, opts)
+		}
+		return gitprovider.NewGitLabProvider(ctx, opts)
+	case strings.HasPrefix(obj.Spec.Type, "Gitea"):
+		token, err := r.getGiteaToken(obj, authData)
+		if err != nil {
+			return nil, err
+		}
+		return gitprovider.NewGiteaProvider(ctx, gitprovider.Options{
+			URL:      url,
+			CertPool: certPool,
+			Token:    token,
+		})
+	case strings.HasPrefix(obj.Spec.Type, "BitbucketServer"):
+		username, password, err := r.getBitbucketServerCredentials(obj, authData)
+		if err != nil {
+			return nil, err
+		}
+		return gitprovider.NewBitbucketServerProvider(ctx, gitprovider.Options{
+			URL:      url,
+			CertPool: certPool,
+			Username: username,
+			Token:    password,
+		})
+	case strings.HasPrefix(obj.Spec.Type, "AzureDevOps"):
+		token, err := r.getAzureDevOpsToken(obj, authData)
+		if err != nil {
+			return nil, err
+		}
+		return gitprovider.NewAzureDevOpsProvider(ctx, gitprovider.Options{
+			URL:      url,
+			CertPool: certPool,
+			Token:    token,
+		})
+	case obj.Spec.Type == fluxcdv1.InputProviderOCIArtifactTag:
+		var keyChain authn.Keychain
+		if obj.Spec.SecretRef != nil {
+			var err error
+			keyChain, err = GetPullSecretKeychain(ctx, r.Client, obj.Spec.SecretRef.Name, obj.GetNamespace())
+			if err != nil {
+				return nil, err
+			}
+		}
+		return gitprovider.NewOCIProvider(ctx, gitprovider.Options{
+			URL:      url,
+			CertPool: certPool,
+			Keychain: keyChain,
 		})
 	default:
 		return nil, fmt.Errorf("unsupported type: %s", obj.Spec.Type)
@@ -253,6 +579,8 @@ func (r *ResourceSetInputProviderReconciler) makeGitOptions(obj *fluxcdv1.Resour
 		Filters: gitprovider.Filters{
 			Limit: 100,
 		},
+		CommitTimestamp: obj.Spec.ExportCommitTimestamp ||
+			(obj.Spec.Expiry != nil && obj.Spec.Expiry.MaxAge != nil),
 	}
 
 	if obj.Spec.Filter != nil {
@@ -276,6 +604,51 @@ func (r *ResourceSetInputProviderReconciler) makeGitOptions(obj *fluxcdv1.Resour
 			}
 			opts.Filters.ExcludeBranchRe = exRx
 		}
+		if obj.Spec.Filter.IncludeTag != "" {
+			inRx, err := regexp.Compile(obj.Spec.Filter.IncludeTag)
+			if err != nil {
+				return gitprovider.Options{}, fmt.Errorf("invalid includeTag regex: %w", err)
+			}
+			opts.Filters.IncludeTagRe = inRx
+		}
+		if obj.Spec.Filter.ExcludeTag != "" {
+			exRx, err := regexp.Compile(obj.Spec.Filter.ExcludeTag)
+			if err != nil {
+				return gitprovider.Options{}, fmt.Errorf("invalid excludeTag regex: %w", err)
+			}
+			opts.Filters.ExcludeTagRe = exRx
+		}
+		if obj.Spec.Filter.Semver != "" {
+			constraint, err := semver.NewConstraint(obj.Spec.Filter.Semver)
+			if err != nil {
+				return gitprovider.Options{}, fmt.Errorf("invalid semver range: %w", err)
+			}
+			opts.Filters.SemverRange = constraint
+		}
+		if obj.Spec.Filter.LatestPerMinor > 0 {
+			opts.Filters.LatestPerMinor = obj.Spec.Filter.LatestPerMinor
+		}
+		if obj.Spec.Filter.IncludeBaseBranch != "" {
+			baseRx, err := regexp.Compile(obj.Spec.Filter.IncludeBaseBranch)
+			if err != nil {
+				return gitprovider.Options{}, fmt.Errorf("invalid includeBaseBranch regex: %w", err)
+			}
+			opts.Filters.BaseBranchRe = baseRx
+		}
+		opts.Filters.ExcludeDraft = obj.Spec.Filter.ExcludeDraft
+		opts.Filters.ExcludeForks = obj.Spec.Filter.ExcludeForks
+		opts.Filters.IncludeAuthors = obj.Spec.Filter.IncludeAuthors
+		opts.Filters.ExcludeAuthors = obj.Spec.Filter.ExcludeAuthors
+		if obj.Spec.Filter.MaxAge != nil {
+			opts.Filters.MaxAge = obj.Spec.Filter.MaxAge.Duration
+		}
+		opts.Filters.Topics = obj.Spec.Filter.Topics
+		opts.Filters.Visibility = obj.Spec.Filter.Visibility
+		opts.Filters.IncludeArchived = obj.Spec.Filter.IncludeArchived
+		opts.Filters.IncludeSubgroups = obj.Spec.Filter.IncludeSubgroups
+		opts.Filters.SortBy = obj.Spec.Filter.Sort
+		opts.Filters.SortDescending = obj.Spec.Filter.SortOrder == "desc"
+		opts.Filters.TitleLabelPrefix = obj.Spec.Filter.TitleLabelPrefix
 	}
 
 	return opts, nil
@@ -333,9 +706,35 @@ func (r *ResourceSetInputProviderReconciler) restoreSkippedGitProviderResults(re
 	return res, nil
 }
 
-func (r *ResourceSetInputProviderReconciler) callProvider(ctx context.Context,
+// restoreLookupResults restores the head commit timestamp and the head
+// repository of the pull/merge requests from the exported inputs, when the
+// provider lookups failed, so that a transient lookup error doesn't change
+// the exported inputs. The timestamp is restored only for the same head commit.
+func restoreLookupResults(obj *fluxcdv1.ResourceSetInputProvider,
+	opts gitprovider.Options,
+	results []gitprovider.Result) {
+	exported := indexInputsByID(obj.Status.ExportedInputs)
+	for i := range results {
+		input, ok := exported[results[i].ID]
+		if !ok {
+			continue
+		}
+		if opts.CommitTimestamp && results[i].CommitTimestamp == "" &&
+			inputString(input, "sha") == results[i].SHA {
+			results[i].CommitTimestamp = inputString(input, "commitTimestamp")
+		}
+		if results[i].Number > 0 && results[i].HeadRepository == "" {
+			results[i].HeadRepository = inputString(input, "headRepository")
+		}
+	}
+}
+
+// callProviders lists the results of the providers and converts them into
+// input sets. When multiple URLs are set, the providers are called concurrently
+// and their results are merged.
+func (r *ResourceSetInputProviderReconciler) callProviders(ctx context.Context,
 	obj *fluxcdv1.ResourceSetInputProvider,
-	provider gitprovider.Interface) ([]fluxcdv1.ResourceSetInput, error) {
+	providers []gitprovider.Interface) ([]fluxcdv1.ResourceSetInput, error) {
 	var inputs []fluxcdv1.ResourceSetInput
 
 	opts, err := r.makeGitOptions(obj)
@@ -343,21 +742,40 @@ func (r *ResourceSetInputProviderReconciler) callProvider(ctx context.Context,
 		return nil, err
 	}
 
+	// The limit is applied after the filter expression is evaluated.
+	var filterExpr string
+	limit := opts.Filters.Limit
+	if obj.Spec.Filter != nil && obj.Spec.Filter.Expr != "" {
+		filterExpr = obj.Spec.Filter.Expr
+		opts.Filters.Limit = 0
+	}
+
+	// Record the number of results dropped by the limit, either
+	// by the providers, when merging or after the filter expression.
 	var results []gitprovider.Result
-	switch {
-	case strings.HasSuffix(obj.Spec.Type, "Branch"):
-		results, err = provider.ListBranches(ctx, opts)
+	var dropped int
+	if len(obj.Spec.URLs) == 0 {
+		results, err = r.listResults(ctx, obj, opts, providers[0])
+		dropped = droppedResults(providers[0])
+	} else {
+		results, dropped, err = r.mergeResults(ctx, obj, opts, providers)
+	}
+	if err != nil {
+		return nil, err
+	}
+	restoreLookupResults(obj, opts, results)
+
+	if filterExpr != "" {
+		results, err = filterResultsByExpr(ctx, filterExpr, results)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list branches: %w", err)
+			return nil, err
 		}
-	case strings.HasSuffix(obj.Spec.Type, "Request"):
-		results, err = provider.ListRequests(ctx, opts)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list requests: %w", err)
+		if len(results) > limit {
+			dropped += len(results) - limit
+			results = results[:limit]
 		}
-	default:
-		return nil, fmt.Errorf("unsupported type: %s", obj.Spec.Type)
 	}
+	obj.Status.DroppedResults = dropped
 
 	if len(results) > 0 {
 		if results, err = r.restoreSkippedGitProviderResults(results, obj); err != nil {
@@ -382,6 +800,111 @@ func (r *ResourceSetInputProviderReconciler) callProvider(ctx context.Context,
 	return inputs, nil
 }
 
+// listResults returns the results of the provider based on the object type.
+func (r *ResourceSetInputProviderReconciler) listResults(ctx context.Context,
+	obj *fluxcdv1.ResourceSetInputProvider,
+	opts gitprovider.Options,
+	provider gitprovider.Interface) ([]gitprovider.Result, error) {
+	switch {
+	case obj.Spec.Type == fluxcdv1.InputProviderGitHubOrganization ||
+		obj.Spec.Type == fluxcdv1.InputProviderGitLabGroup:
+		lister, ok := provider.(gitprovider.RepositoryLister)
+		if !ok {
+			return nil, fmt.Errorf("unsupported type: %s", obj.Spec.Type)
+		}
+		results, err := lister.ListRepositories(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+		return results, nil
+	case strings.HasSuffix(obj.Spec.Type, "Branch"):
+		results, err := provider.ListBranches(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches: %w", err)
+		}
+		return results, nil
+	case strings.HasSuffix(obj.Spec.Type, "Request"):
+		results, err := provider.ListRequests(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list requests: %w", err)
+		}
+		return results, nil
+	case strings.HasSuffix(obj.Spec.Type, "Tag"):
+		results, err := provider.ListTags(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: %w", err)
+		}
+		return results, nil
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", obj.Spec.Type)
+	}
+}
+
+// recordRateLimit records the remaining quota metric of each URL, and sets the
+// RateLimited condition from the API rate limit status of the most constrained
+// provider, which is returned.
+func (r *ResourceSetInputProviderReconciler) recordRateLimit(obj *fluxcdv1.ResourceSetInputProvider,
+	urls []string,
+	providers []gitprovider.Interface) *gitprovider.RateLimit {
+	reporter.DeleteRateLimits(obj.GetName(), obj.GetNamespace())
+
+	var rateLimit *gitprovider.RateLimit
+	for i, provider := range providers {
+		rlr, ok := provider.(gitprovider.RateLimitReporter)
+		if !ok {
+			continue
+		}
+		rl := rlr.RateLimit()
+		if rl == nil {
+			continue
+		}
+		reporter.RecordRateLimit(obj.GetName(), obj.GetNamespace(), urls[i], rl.Remaining)
+		if rateLimit == nil || moreConstrained(rl, rateLimit) {
+			rateLimit = rl
+		}
+	}
+	if rateLimit == nil {
+		return nil
+	}
+
+	if rateLimit.Exceeded() {
+		conditions.MarkTrue(obj,
+			fluxcdv1.RateLimitedCondition,
+			fluxcdv1.RateLimitExceededReason,
+			"API rate limit exceeded, requests can be resumed at %s",
+			rateLimit.RetryAt.UTC().Format(time.RFC3339))
+		return rateLimit
+	}
+
+	quota := fmt.Sprintf("%d", rateLimit.Remaining)
+	if rateLimit.Limit > 0 {
+		quota = fmt.Sprintf("%d/%d", rateLimit.Remaining, rateLimit.Limit)
+	}
+	if !rateLimit.ResetAt.IsZero() {
+		quota = fmt.Sprintf("%s, resets at %s", quota, rateLimit.ResetAt.UTC().Format(time.RFC3339))
+	}
+	conditions.MarkFalse(obj,
+		fluxcdv1.RateLimitedCondition,
+		fluxcdv1.RateLimitAvailableReason,
+		"API rate limit remaining %s", quota)
+
+	return rateLimit
+}
+
+// moreConstrained returns true if the rate limit a is more constrained than b,
+// a rate limit that is exceeded until a later time, or else that has fewer
+// remaining requests.
+func moreConstrained(a, b *gitprovider.RateLimit) bool {
+	switch {
+	case a.Exceeded() != b.Exceeded():
+		return a.Exceeded()
+	case a.Exceeded():
+		return a.RetryAt.After(b.RetryAt)
+	default:
+		return a.Remaining < b.Remaining
+	}
+}
+
 // getBasicAuth returns the basic auth credentials by reading the username
 // and password from authData.
 //
@@ -404,9 +927,11 @@ func (r *ResourceSetInputProviderReconciler) getBasicAuth(
 }
 
 // getGitHubToken returns the appropriate GitHub token by reading the secrets in authData.
+// For GitHub App credentials, an installation token is minted and cached until expiry.
 func (r *ResourceSetInputProviderReconciler) getGitHubToken(
 	ctx context.Context,
 	obj *fluxcdv1.ResourceSetInputProvider,
+	url string,
 	authData map[string][]byte) (string, error) {
 
 	if authData == nil {
@@ -420,6 +945,18 @@ func (r *ResourceSetInputProviderReconciler) getGitHubToken(
 
 	opts := []github.OptFunc{github.WithAppData(authData)}
 
+	// Default the GitHub App API endpoint to the GitHub Enterprise
+	// host of the provider URL if not set in the secret.
+	if _, ok := authData[github.AppBaseUrlKey]; !ok {
+		appBaseURL, err := gitprovider.GitHubAppBaseURL(url)
+		if err != nil {
+			return "", err
+		}
+		if appBaseURL != "" {
+			opts = append(opts, github.WithAppBaseURL(appBaseURL))
+		}
+	}
+
 	if r.TokenCache != nil {
 		opts = append(opts, github.WithCache(r.TokenCache,
 			fluxcdv1.ResourceSetInputProviderKind,
@@ -454,6 +991,46 @@ func (r *ResourceSetInputProviderReconciler) getGitLabToken(
 	return password, err
 }
 
+// getGiteaToken returns the appropriate Gitea token by reading the secrets in authData.
+func (r *ResourceSetInputProviderReconciler) getGiteaToken(
+	obj *fluxcdv1.ResourceSetInputProvider,
+	authData map[string][]byte) (string, error) {
+
+	if authData == nil {
+		return "", nil
+	}
+
+	_, password, err := r.getBasicAuth(obj, authData)
+	return password, err
+}
+
+// getBitbucketServerCredentials returns the Bitbucket Server username and password
+// or HTTP access token by reading the secrets in authData. When the username is
+// empty, the password is used as a bearer HTTP access token.
+func (r *ResourceSetInputProviderReconciler) getBitbucketServerCredentials(
+	obj *fluxcdv1.ResourceSetInputProvider,
+	authData map[string][]byte) (string, string, error) {
+
+	if authData == nil {
+		return "", "", nil
+	}
+
+	return r.getBasicAuth(obj, authData)
+}
+
+// getAzureDevOpsToken returns the Azure DevOps personal access token by reading the secrets in authData.
+func (r *ResourceSetInputProviderReconciler) getAzureDevOpsToken(
+	obj *fluxcdv1.ResourceSetInputProvider,
+	authData map[string][]byte) (string, error) {
+
+	if authData == nil {
+		return "", nil
+	}
+
+	_, password, err := r.getBasicAuth(obj, authData)
+	return password, err
+}
+
 // getCertPool returns the x509.CertPool by reading the CA certificate from
 // spec.CertSecretRef.
 func (r *ResourceSetInputProviderReconciler) getCertPool(ctx context.Context,
@@ -530,6 +1107,8 @@ func (r *ResourceSetInputProviderReconciler) patch(ctx context.Context,
 		meta.ReadyCondition,
 		meta.ReconcilingCondition,
 		meta.StalledCondition,
+		fluxcdv1.RateLimitedCondition,
+		fluxcdv1.FrozenCondition,
 	}
 	patchOpts := []patch.Option{
 		patch.WithOwnedConditions{Conditions: ownedConditions},
@@ -587,3 +1166,9 @@ func requeueAfterResourceSetInputProvider(obj *fluxcdv1.ResourceSetInputProvider
 
 	return result
 }
+
+// checksum returns the Adler-32 checksum of the data used as input set id,
+// the same checksum is used for the ids of the Git provider results.
+func checksum(data []byte) string {
+	return fmt.Sprintf("%v", adler32.Checksum(data))
+}
@@ -6,7 +6,10 @@ package controller
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,6 +19,7 @@ import (
 	"github.com/fluxcd/pkg/runtime/conditions"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -23,6 +27,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/gitprovider"
 )
 
 func TestResourceSetInputProviderReconciler_GitLabBranch_LifeCycle(t *testing.T) {
@@ -535,7 +540,7 @@ func TestResourceSetInputProviderReconciler_getGitHubToken_cached(t *testing.T)
 	privateKeyPEM, err := os.ReadFile("testdata/rsa-private-key.pem")
 	g.Expect(err).NotTo(HaveOccurred())
 
-	token, err := r.getGitHubToken(ctx, &fluxcdv1.ResourceSetInputProvider{}, map[string][]byte{
+	token, err := r.getGitHubToken(ctx, &fluxcdv1.ResourceSetInputProvider{}, "", map[string][]byte{
 		"githubAppID":             []byte("123"),
 		"githubAppInstallationID": []byte("123456"),
 		"githubAppBaseURL":        []byte("https://github.com"),
@@ -546,6 +551,39 @@ func TestResourceSetInputProviderReconciler_getGitHubToken_cached(t *testing.T)
 	g.Expect(token).To(Equal("my-gh-app-token"))
 }
 
+func TestRestoreLookupResults(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &fluxcdv1.ResourceSetInputProvider{}
+	obj.Status.ExportedInputs = []fluxcdv1.ResourceSetInput{
+		{
+			"id":              &apiextensionsv1.JSON{Raw: []byte(`"1"`)},
+			"sha":             &apiextensionsv1.JSON{Raw: []byte(`"a1"`)},
+			"headRepository":  &apiextensionsv1.JSON{Raw: []byte(`"mallory/app"`)},
+			"commitTimestamp": &apiextensionsv1.JSON{Raw: []byte(`"2025-01-01T00:00:00Z"`)},
+		},
+		{
+			"id":              &apiextensionsv1.JSON{Raw: []byte(`"2"`)},
+			"sha":             &apiextensionsv1.JSON{Raw: []byte(`"old"`)},
+			"headRepository":  &apiextensionsv1.JSON{Raw: []byte(`"org/app"`)},
+			"commitTimestamp": &apiextensionsv1.JSON{Raw: []byte(`"2025-01-01T00:00:00Z"`)},
+		},
+	}
+
+	results := []gitprovider.Result{
+		{ID: "1", SHA: "a1", Number: 1},
+		{ID: "2", SHA: "a2", Number: 2},
+		{ID: "3", SHA: "a3", Number: 3},
+	}
+	restoreLookupResults(obj, gitprovider.Options{CommitTimestamp: true}, results)
+
+	g.Expect(results).To(Equal([]gitprovider.Result{
+		{ID: "1", SHA: "a1", Number: 1, HeadRepository: "mallory/app", CommitTimestamp: "2025-01-01T00:00:00Z"},
+		{ID: "2", SHA: "a2", Number: 2, HeadRepository: "org/app"},
+		{ID: "3", SHA: "a3", Number: 3},
+	}))
+}
+
 func getResourceSetInputProviderReconciler() *ResourceSetInputProviderReconciler {
 	return &ResourceSetInputProviderReconciler{
 		Client:        testClient,
@@ -722,3 +760,511 @@ spec:
 		})
 	}
 }
+
+func TestResourceSetInputProviderReconciler_KubernetesSelector_LifeCycle(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getResourceSetInputProviderReconciler()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Create the objects to be selected.
+	for i, tenant := range []string{"tenant1", "tenant2", "tenant3"} {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tenant,
+				Namespace: ns.Name,
+				Labels: map[string]string{
+					"tenant": tenant,
+				},
+			},
+			Data: map[string]string{
+				"region": "eu-west-1",
+			},
+		}
+		if i < 2 {
+			cm.Labels["app"] = "tenants"
+		}
+		err = testClient.Create(ctx, cm)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	objDef := fmt.Sprintf(`
+apiVersion: fluxcd.controlplane.io/v1
+kind: ResourceSetInputProvider
+metadata:
+  name: test-selector
+  namespace: "%[1]s"
+spec:
+  type: KubernetesSelector
+  selector:
+    apiVersion: v1
+    kind: ConfigMap
+    labelSelector:
+      matchLabels:
+        app: tenants
+    fields:
+      tenant: "{.metadata.labels.tenant}"
+      region: "{.data.region}"
+      missing: "{.metadata.annotations.missing}"
+  defaultValues:
+    env: "staging"
+`, ns.Name)
+
+	obj := &fluxcdv1.ResourceSetInputProvider{}
+	err = yaml.Unmarshal([]byte(objDef), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Create the ResourceSetInputProvider.
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Initialize the ResourceSetInputProvider.
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	// Retrieve the inputs.
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Check if the inputs were exported from the selected objects.
+	result := &fluxcdv1.ResourceSetInputProvider{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.IsReady(result)).To(BeTrue())
+
+	inputs, err := result.GetInputs()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inputs).To(HaveLen(2))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("name", "tenant1"))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("namespace", ns.Name))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("tenant", "tenant1"))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("region", "eu-west-1"))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("env", "staging"))
+	g.Expect(inputs[0]).ToNot(HaveKey("missing"))
+	g.Expect(inputs[1]).To(HaveKeyWithValue("name", "tenant2"))
+
+	// Label the third object to be selected.
+	cm := &corev1.ConfigMap{}
+	err = testClient.Get(ctx, client.ObjectKey{Name: "tenant3", Namespace: ns.Name}, cm)
+	g.Expect(err).ToNot(HaveOccurred())
+	cmP := cm.DeepCopy()
+	cmP.Labels["app"] = "tenants"
+	err = testClient.Patch(ctx, cmP, client.MergeFrom(cm))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Check if the exported inputs were updated.
+	resultFinal := &fluxcdv1.ResourceSetInputProvider{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), resultFinal)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resultFinal.Status.ExportedInputs).To(HaveLen(3))
+	g.Expect(resultFinal.Status.LastExportedRevision).ToNot(Equal(result.Status.LastExportedRevision))
+
+	// Delete the ResourceSetInputProvider.
+	err = testClient.Delete(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.IsZero()).To(BeTrue())
+}
+
+func TestResourceSetInputProviderReconciler_HTTPJSON_LifeCycle(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getResourceSetInputProviderReconciler()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Serve the JSON document behind bearer token authentication.
+	response := `{"tenants":[
+{"name":"tenant1","enabled":true,"location":{"region":"eu-west-1"}},
+{"name":"tenant2","enabled":false,"location":{"region":"us-east-1"}},
+{"name":"tenant3","enabled":true}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "api-token",
+			Namespace: ns.Name,
+		},
+		StringData: map[string]string{
+			"username": "",
+			"password": "test-token",
+		},
+	}
+	err = testClient.Create(ctx, secret)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objDef := fmt.Sprintf(`
+apiVersion: fluxcd.controlplane.io/v1
+kind: ResourceSetInputProvider
+metadata:
+  name: test-http
+  namespace: "%[1]s"
+spec:
+  type: HTTPJSON
+  url: "%[2]s"
+  json:
+    itemsExpr: "response.tenants.filter(t, t.enabled)"
+    id: "{.name}"
+    fields:
+      tenant: "{.name}"
+      region: "{.location.region}"
+  secretRef:
+    name: api-token
+  defaultValues:
+    region: "eu-central-1"
+`, ns.Name, server.URL)
+
+	obj := &fluxcdv1.ResourceSetInputProvider{}
+	err = yaml.Unmarshal([]byte(objDef), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Create the ResourceSetInputProvider.
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Initialize the ResourceSetInputProvider.
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	// Retrieve the inputs.
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Check if the inputs were exported from the JSON items.
+	result := &fluxcdv1.ResourceSetInputProvider{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.IsReady(result)).To(BeTrue())
+
+	inputs, err := result.GetInputs()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inputs).To(HaveLen(2))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("id", "194904764"))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("tenant", "tenant1"))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("region", "eu-west-1"))
+	g.Expect(inputs[1]).To(HaveKeyWithValue("tenant", "tenant3"))
+	g.Expect(inputs[1]).To(HaveKeyWithValue("region", "eu-central-1"))
+
+	// Update the JSON document to drop a tenant.
+	response = `{"tenants":[{"name":"tenant1","enabled":true}]}`
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Check if the exported inputs were updated.
+	resultFinal := &fluxcdv1.ResourceSetInputProvider{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), resultFinal)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resultFinal.Status.ExportedInputs).To(HaveLen(1))
+	g.Expect(resultFinal.Status.LastExportedRevision).ToNot(Equal(result.Status.LastExportedRevision))
+
+	// Delete the ResourceSetInputProvider.
+	err = testClient.Delete(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.IsZero()).To(BeTrue())
+}
+
+func TestResourceSetInputProviderReconciler_InvalidSchedule(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getResourceSetInputProviderReconciler()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"tenants":[{"name":"tenant1"}]}`))
+	}))
+	defer server.Close()
+
+	objDef := fmt.Sprintf(`
+apiVersion: fluxcd.controlplane.io/v1
+kind: ResourceSetInputProvider
+metadata:
+  name: test-schedule
+  namespace: "%[1]s"
+spec:
+  type: HTTPJSON
+  url: "%[2]s"
+  json:
+    itemsExpr: "response.tenants"
+    id: "{.name}"
+  schedule:
+    - cron: "0 9 * * *"
+      timeZone: "Mars/Olympus"
+      window: "8h"
+`, ns.Name, server.URL)
+
+	obj := &fluxcdv1.ResourceSetInputProvider{}
+	err = yaml.Unmarshal([]byte(objDef), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Create the ResourceSetInputProvider.
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Initialize the ResourceSetInputProvider.
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	// Check that the reconciliation is stalled without calling the provider.
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.IsZero()).To(BeTrue())
+	g.Expect(requests.Load()).To(BeZero())
+
+	result := &fluxcdv1.ResourceSetInputProvider{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.GetReason(result, meta.ReadyCondition)).To(BeIdenticalTo(fluxcdv1.InvalidScheduleReason))
+	g.Expect(conditions.IsStalled(result)).To(BeTrue())
+	g.Expect(conditions.GetMessage(result, meta.ReadyCondition)).To(ContainSubstring("invalid schedule[0] time zone"))
+
+	// Fix the schedule.
+	resultP := result.DeepCopy()
+	resultP.Spec.Schedule[0].TimeZone = "Europe/London"
+	err = testClient.Patch(ctx, resultP, client.MergeFrom(result))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(requests.Load()).To(BeEquivalentTo(1))
+
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(conditions.IsStalled(result)).To(BeFalse())
+
+	// Delete the ResourceSetInputProvider.
+	err = testClient.Delete(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.IsZero()).To(BeTrue())
+}
+
+func TestResourceSetInputProviderReconciler_FilterExprAndTransform(t *testing.T) {
+	g := NewWithT(t)
+	reconciler := getResourceSetInputProviderReconciler()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Serve the pull requests from a GitHub Enterprise API.
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/org/app/pulls", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+{"number":4,"title":"[preview] feat 4","user":{"login":"alice"},"labels":[{"name":"a"},{"name":"b"},{"name":"c"}],
+ "head":{"ref":"feat/4","sha":"a4a4a4a4a4"},"base":{"ref":"main"}},
+{"number":3,"title":"[preview] Feat 3","user":{"login":"bob"},"labels":[{"name":"a"}],
+ "head":{"ref":"Feat/3","sha":"a3a3a3a3a3"},"base":{"ref":"main"}},
+{"number":2,"title":"fix 2","user":{"login":"bob"},
+ "head":{"ref":"fix/2","sha":"a2a2a2a2a2"},"base":{"ref":"main"}},
+{"number":1,"title":"[preview] feat 1","user":{"login":"alice"},
+ "head":{"ref":"feat/1","sha":"a1a1a1a1a1"},"base":{"ref":"main"}}
+]`))
+	})
+	mux.HandleFunc("/api/v3/repos/org/app/git/commits/", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"committer":{"date":"2025-01-02T10:00:00Z"}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	objDef := fmt.Sprintf(`
+apiVersion: fluxcd.controlplane.io/v1
+kind: ResourceSetInputProvider
+metadata:
+  name: test-expr
+  namespace: "%[1]s"
+spec:
+  type: GitHubPullRequest
+  url: "%[2]s/org/app"
+  filter:
+    expr: "title.startsWith('[preview]') && size(labels) < 3"
+    limit: 1
+  transform:
+    shortSha: "sha.substring(0, 7)"
+    env: "'pr-' + id + '-' + branch.lowerAscii().replace('/', '-')"
+`, ns.Name, server.URL)
+
+	obj := &fluxcdv1.ResourceSetInputProvider{}
+	err = yaml.Unmarshal([]byte(objDef), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Initialize the ResourceSetInputProvider.
+	r, err := reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Requeue).To(BeTrue())
+
+	// Retrieve the inputs.
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	result := &fluxcdv1.ResourceSetInputProvider{}
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	logObjectStatus(t, result)
+	g.Expect(conditions.IsReady(result)).To(BeTrue())
+
+	// Check if the limit was applied after the filter expression.
+	inputs, err := result.GetInputs()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inputs).To(HaveLen(1))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("id", "3"))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("shortSha", "a3a3a3a"))
+	g.Expect(inputs[0]).To(HaveKeyWithValue("env", "pr-3-feat-3"))
+
+	// Update the transform with an invalid expression.
+	resultP := result.DeepCopy()
+	resultP.Spec.Transform = map[string]string{"env": "missing + 'a'"}
+	err = testClient.Patch(ctx, resultP, client.MergeFrom(result))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).To(HaveOccurred())
+
+	err = testClient.Get(ctx, client.ObjectKeyFromObject(obj), result)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(conditions.IsReady(result)).To(BeFalse())
+	g.Expect(conditions.GetMessage(result, meta.ReadyCondition)).To(ContainSubstring("no such attribute"))
+
+	// Delete the ResourceSetInputProvider.
+	err = testClient.Delete(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	r, err = reconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(obj),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.IsZero()).To(BeTrue())
+}
+
+func TestResourceSetInputProviderReconciler_RequiredURL(t *testing.T) {
+	g := NewWithT(t)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	obj := &fluxcdv1.ResourceSetInputProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-no-url",
+			Namespace: ns.Name,
+		},
+		Spec: fluxcdv1.ResourceSetInputProviderSpec{
+			Type: fluxcdv1.InputProviderGitHubBranch,
+		},
+	}
+
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("spec.url is required for this type"))
+}
+
+func TestResourceSetInputProviderReconciler_GitLabReviewReapproval(t *testing.T) {
+	g := NewWithT(t)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ns, err := testEnv.CreateNamespace(ctx, "test")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	obj := &fluxcdv1.ResourceSetInputProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-gitlab-review",
+			Namespace: ns.Name,
+		},
+		Spec: fluxcdv1.ResourceSetInputProviderSpec{
+			Type: fluxcdv1.InputProviderGitLabMergeRequest,
+			URL:  "https://gitlab.com/stefanprodan/podinfo",
+			Approval: &fluxcdv1.ResourceSetInputApproval{
+				Review:               true,
+				Approvers:            []string{"stefanprodan"},
+				ReapproveOnNewCommit: true,
+			},
+		},
+	}
+
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("spec.approval.review is not supported with reapproveOnNewCommit"))
+
+	// The label approval of new commits is supported.
+	obj.Spec.Approval.Review = false
+	obj.Spec.Approval.Label = "deploy"
+	err = testEnv.Create(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+// defaultExpiryWarning is the default duration before the
+// expiry when the expiring input sets are reported.
+const defaultExpiryWarning = 24 * time.Hour
+
+// inputsExpiry holds the ids of the input sets that entered the warning
+// period or expired in the current reconciliation, and the time of the
+// next warning or expiry.
+type inputsExpiry struct {
+	expiring []string
+	expired  []string
+	next     time.Time
+}
+
+// expireInputs records the activity of the input sets in the object status and
+// returns the input sets that have not expired. An input set expires when its
+// 'commitTimestamp' is older than the max age, or when it has not changed for
+// the max inactivity duration. The input sets without an id, or with the keep
+// label in their 'labels' input, never expire.
+func expireInputs(obj *fluxcdv1.ResourceSetInputProvider,
+	inputs []fluxcdv1.ResourceSetInput,
+	now time.Time) ([]fluxcdv1.ResourceSetInput, inputsExpiry, error) {
+	var result inputsExpiry
+	spec := obj.Spec.Expiry
+	if spec == nil {
+		obj.Status.InputsActivity = nil
+		return inputs, result, nil
+	}
+
+	warning := defaultExpiryWarning
+	if spec.WarningBefore != nil {
+		warning = spec.WarningBefore.Duration
+	}
+
+	previous := make(map[string]fluxcdv1.InputActivity, len(obj.Status.InputsActivity))
+	for _, a := range obj.Status.InputsActivity {
+		previous[a.ID] = a
+	}
+
+	kept := make([]fluxcdv1.ResourceSetInput, 0, len(inputs))
+	activity := make([]fluxcdv1.InputActivity, 0, len(inputs))
+	for _, input := range inputs {
+		id, ok := inputID(input)
+		if !ok {
+			kept = append(kept, input)
+			continue
+		}
+
+		d, err := inputDigest(input)
+		if err != nil {
+			return nil, result, fmt.Errorf("failed to compute the digest of input set %s: %w", id, err)
+		}
+
+		a := fluxcdv1.InputActivity{
+			ID:          id,
+			Digest:      d,
+			LastChanged: metav1.NewTime(now.Truncate(time.Second)),
+		}
+		prev, found := previous[id]
+		if found && prev.Digest == d {
+			a.LastChanged = prev.LastChanged
+		}
+
+		if expiresAt, ok := inputExpiresAt(spec, input, a.LastChanged.Time); ok {
+			a.ExpiresAt = &metav1.Time{Time: expiresAt}
+			sameExpiry := found && prev.ExpiresAt != nil && prev.ExpiresAt.Equal(a.ExpiresAt)
+			switch {
+			case !now.Before(expiresAt):
+				a.Expired = true
+				if !found || !prev.Expired {
+					result.expired = append(result.expired, id)
+				}
+			case !now.Before(expiresAt.Add(-warning)):
+				a.Warned = true
+				if !sameExpiry || !prev.Warned {
+					result.expiring = append(result.expiring, id)
+				}
+				result.scheduleNext(expiresAt)
+			default:
+				result.scheduleNext(expiresAt.Add(-warning))
+			}
+		}
+
+		activity = append(activity, a)
+		if !a.Expired {
+			kept = append(kept, input)
+		}
+	}
+
+	obj.Status.InputsActivity = activity
+	return kept, result, nil
+}
+
+// scheduleNext sets the time of the next warning or expiry if it is earlier.
+func (e *inputsExpiry) scheduleNext(t time.Time) {
+	if e.next.IsZero() || t.Before(e.next) {
+		e.next = t
+	}
+}
+
+// inputExpiresAt returns the expiry time of the input set, or false if the
+// input set has the keep label or no expiry rule applies to it.
+func inputExpiresAt(spec *fluxcdv1.ResourceSetInputExpiry,
+	input fluxcdv1.ResourceSetInput,
+	lastChanged time.Time) (time.Time, bool) {
+	if spec.KeepLabel != "" && slices.Contains(inputLabels(input), spec.KeepLabel) {
+		return time.Time{}, false
+	}
+
+	var expiresAt time.Time
+	if spec.MaxAge != nil {
+		if commitTime, err := time.Parse(time.RFC3339, inputString(input, "commitTimestamp")); err == nil {
+			expiresAt = commitTime.Add(spec.MaxAge.Duration)
+		}
+	}
+	if spec.MaxInactivity != nil {
+		if t := lastChanged.Add(spec.MaxInactivity.Duration); expiresAt.IsZero() || t.Before(expiresAt) {
+			expiresAt = t
+		}
+	}
+
+	return expiresAt, !expiresAt.IsZero()
+}
+
+// inputID returns the 'id' input as a string, or false if the input set has no id.
+func inputID(input fluxcdv1.ResourceSetInput) (string, bool) {
+	v, ok := input["id"]
+	if !ok || v == nil {
+		return "", false
+	}
+
+	var value any
+	if err := json.Unmarshal(v.Raw, &value); err != nil || value == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%v", value), true
+}
+
+// inputString returns the value of the string input, or an empty string
+// if the input is not set or is not a string.
+func inputString(input fluxcdv1.ResourceSetInput, key string) string {
+	var value string
+	if v, ok := input[key]; ok && v != nil {
+		_ = json.Unmarshal(v.Raw, &value)
+	}
+	return value
+}
+
+// inputLabels returns the 'labels' input of the pull/merge request input sets.
+func inputLabels(input fluxcdv1.ResourceSetInput) []string {
+	var labels []string
+	if v, ok := input["labels"]; ok && v != nil {
+		_ = json.Unmarshal(v.Raw, &labels)
+	}
+	return labels
+}
+
+// inputDigest returns the digest of the 'sha' input,
+// or of the whole input set when it has no 'sha'.
+func inputDigest(input fluxcdv1.ResourceSetInput) (string, error) {
+	if sha, ok := input["sha"]; ok && sha != nil {
+		return digest.FromBytes(sha.Raw).String(), nil
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(data).String(), nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+func TestExpireInputs(t *testing.T) {
+	now := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	input := func(fields map[string]string) fluxcdv1.ResourceSetInput {
+		in := make(fluxcdv1.ResourceSetInput, len(fields))
+		for k, v := range fields {
+			in[k] = &apiextensionsv1.JSON{Raw: []byte(v)}
+		}
+		return in
+	}
+	activity := func(id, sha string, lastChanged time.Time, expiresAt time.Time, warned bool) fluxcdv1.InputActivity {
+		in := input(map[string]string{"id": `"` + id + `"`, "sha": `"` + sha + `"`})
+		d, _ := inputDigest(in)
+		return fluxcdv1.InputActivity{
+			ID:          id,
+			Digest:      d,
+			LastChanged: metav1.NewTime(lastChanged),
+			ExpiresAt:   &metav1.Time{Time: expiresAt},
+			Warned:      warned,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		expiry   *fluxcdv1.ResourceSetInputExpiry
+		previous []fluxcdv1.InputActivity
+		inputs   []fluxcdv1.ResourceSetInput
+		kept     []string
+		expiring []string
+		expired  []string
+		next     time.Time
+	}{
+		{
+			name: "no expiry",
+			inputs: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "commitTimestamp": `"2020-01-01T00:00:00Z"`}),
+			},
+			kept: []string{"1"},
+		},
+		{
+			name:   "max age",
+			expiry: &fluxcdv1.ResourceSetInputExpiry{MaxAge: &metav1.Duration{Duration: 30 * day}},
+			inputs: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "commitTimestamp": `"2025-03-01T00:00:00Z"`}),
+				input(map[string]string{"id": `"2"`, "commitTimestamp": `"2025-03-11T18:00:00Z"`}),
+				input(map[string]string{"id": `"3"`, "commitTimestamp": `"2025-04-01T00:00:00Z"`}),
+				input(map[string]string{"id": `"4"`}),
+			},
+			kept:     []string{"2", "3", "4"},
+			expiring: []string{"2"},
+			expired:  []string{"1"},
+			next:     time.Date(2025, 4, 10, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "keep label",
+			expiry: &fluxcdv1.ResourceSetInputExpiry{
+				MaxAge:    &metav1.Duration{Duration: day},
+				KeepLabel: "keep-alive",
+			},
+			inputs: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "commitTimestamp": `"2025-03-01T00:00:00Z"`, "labels": `["keep-alive"]`}),
+				input(map[string]string{"id": `"2"`, "commitTimestamp": `"2025-03-01T00:00:00Z"`, "labels": `["bug"]`}),
+			},
+			kept:    []string{"1"},
+			expired: []string{"2"},
+		},
+		{
+			name:   "max inactivity",
+			expiry: &fluxcdv1.ResourceSetInputExpiry{MaxInactivity: &metav1.Duration{Duration: 7 * day}},
+			previous: []fluxcdv1.InputActivity{
+				activity("1", "a", now.Add(-8*day), now.Add(-day), false),
+				activity("2", "b", now.Add(-6*day-time.Hour), now.Add(day-time.Hour), false),
+				activity("3", "c", now.Add(-10*day), now.Add(-3*day), false),
+				activity("4", "d", now.Add(-day), now.Add(6*day), false),
+			},
+			inputs: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "sha": `"a"`}),
+				input(map[string]string{"id": `"2"`, "sha": `"b"`}),
+				input(map[string]string{"id": `"3"`, "sha": `"changed"`}),
+				input(map[string]string{"id": `"4"`, "sha": `"d"`}),
+				input(map[string]string{"id": `"5"`, "sha": `"e"`}),
+			},
+			kept:     []string{"2", "3", "4", "5"},
+			expiring: []string{"2"},
+			expired:  []string{"1"},
+			next:     now.Add(day - time.Hour),
+		},
+		{
+			name: "warning and expiry are reported once",
+			expiry: &fluxcdv1.ResourceSetInputExpiry{
+				MaxInactivity: &metav1.Duration{Duration: 7 * day},
+				WarningBefore: &metav1.Duration{Duration: 2 * day},
+			},
+			previous: []fluxcdv1.InputActivity{
+				activity("1", "a", now.Add(-6*day), now.Add(day), true),
+				func() fluxcdv1.InputActivity {
+					a := activity("2", "b", now.Add(-8*day), now.Add(-day), false)
+					a.Expired = true
+					return a
+				}(),
+			},
+			inputs: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "sha": `"a"`}),
+				input(map[string]string{"id": `"2"`, "sha": `"b"`}),
+			},
+			kept: []string{"1"},
+			next: now.Add(day),
+		},
+		{
+			name: "earliest of max age and max inactivity",
+			expiry: &fluxcdv1.ResourceSetInputExpiry{
+				MaxAge:        &metav1.Duration{Duration: 30 * day},
+				MaxInactivity: &metav1.Duration{Duration: 7 * day},
+			},
+			inputs: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "sha": `"a"`, "commitTimestamp": `"2025-04-10T00:00:00Z"`}),
+			},
+			kept: []string{"1"},
+			next: now.Add(6 * day),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &fluxcdv1.ResourceSetInputProvider{}
+			obj.Spec.Expiry = tt.expiry
+			obj.Status.InputsActivity = tt.previous
+
+			kept, expiry, err := expireInputs(obj, tt.inputs, now)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var keptIDs []string
+			for _, in := range kept {
+				id, _ := inputID(in)
+				keptIDs = append(keptIDs, id)
+			}
+			g.Expect(keptIDs).To(Equal(tt.kept))
+			g.Expect(expiry.expiring).To(ConsistOf(tt.expiring))
+			g.Expect(expiry.expired).To(ConsistOf(tt.expired))
+			g.Expect(expiry.next.Equal(tt.next)).To(BeTrue(), "expected next %s, got %s", tt.next, expiry.next)
+
+			if tt.expiry == nil {
+				g.Expect(obj.Status.InputsActivity).To(BeEmpty())
+			} else {
+				g.Expect(obj.Status.InputsActivity).To(HaveLen(len(tt.inputs)))
+			}
+		})
+	}
+}
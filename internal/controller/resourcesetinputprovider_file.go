@@ -0,0 +1,316 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/gitprovider"
+)
+
+const sourceRefIndexKey string = ".spec.sourceRef"
+
+// maxInputsFileSize is the maximum size of the inputs file
+// and of the Flux source artifact containing it.
+const maxInputsFileSize = 10 << 20
+
+// fluxSourceAPIVersions maps the Flux source kinds to their API version.
+var fluxSourceAPIVersions = map[string]string{
+	"GitRepository": "source.toolkit.fluxcd.io/v1",
+	"OCIRepository": "source.toolkit.fluxcd.io/v1beta2",
+}
+
+// readGitFile reads the inputs file from the Git repository
+// and returns the input sets and the SHA of the commit.
+func (r *ResourceSetInputProviderReconciler) readGitFile(ctx context.Context,
+	obj *fluxcdv1.ResourceSetInputProvider,
+	provider gitprovider.Interface) ([]fluxcdv1.ResourceSetInput, string, error) {
+	reader, ok := provider.(gitprovider.FileReader)
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported type: %s", obj.Spec.Type)
+	}
+	if obj.Spec.File == nil {
+		return nil, "", errors.New("spec.file is required for this type")
+	}
+
+	content, revision, err := reader.ReadFile(ctx, obj.Spec.File.Path, obj.Spec.File.Ref)
+	if err != nil {
+		return nil, "", err
+	}
+
+	inputs, err := parseInputsFile(obj, content)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return inputs, revision, nil
+}
+
+// readFluxArtifact downloads the artifact of the Flux source, verifies its
+// digest and returns the input sets from the inputs file together with
+// the artifact revision.
+func (r *ResourceSetInputProviderReconciler) readFluxArtifact(ctx context.Context,
+	obj *fluxcdv1.ResourceSetInputProvider) ([]fluxcdv1.ResourceSetInput, string, error) {
+	if obj.Spec.SourceRef == nil || obj.Spec.File == nil {
+		return nil, "", errors.New("spec.sourceRef and spec.file are required for this type")
+	}
+
+	apiVersion, ok := fluxSourceAPIVersions[obj.Spec.SourceRef.Kind]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported source kind: %s", obj.Spec.SourceRef.Kind)
+	}
+
+	source := &unstructured.Unstructured{}
+	source.SetGroupVersionKind(schema.FromAPIVersionAndKind(apiVersion, obj.Spec.SourceRef.Kind))
+	sourceKey := client.ObjectKey{Name: obj.Spec.SourceRef.Name, Namespace: obj.GetNamespace()}
+	if err := r.Get(ctx, sourceKey, source); err != nil {
+		return nil, "", fmt.Errorf("failed to get %s/%s: %w", obj.Spec.SourceRef.Kind, sourceKey, err)
+	}
+
+	url, _, _ := unstructured.NestedString(source.Object, "status", "artifact", "url")
+	revision, _, _ := unstructured.NestedString(source.Object, "status", "artifact", "revision")
+	artifactDigest, _, _ := unstructured.NestedString(source.Object, "status", "artifact", "digest")
+	if url == "" {
+		return nil, "", fmt.Errorf("%s/%s has no artifact", obj.Spec.SourceRef.Kind, sourceKey)
+	}
+
+	if err := r.watchSource(source.GroupVersionKind()); err != nil {
+		return nil, "", fmt.Errorf("failed to watch %s: %w", obj.Spec.SourceRef.Kind, err)
+	}
+
+	content, err := fetchArtifactFile(ctx, url, artifactDigest, obj.Spec.File.Path, obj.GetTimeout())
+	if err != nil {
+		return nil, "", err
+	}
+
+	inputs, err := parseInputsFile(obj, content)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return inputs, revision, nil
+}
+
+// fetchArtifactFile downloads the tarball from the artifact URL and returns
+// the content of the file at the given path. When the expected digest is set,
+// the tarball content is verified against it. The download is aborted when
+// it takes longer than the given timeout.
+func fetchArtifactFile(ctx context.Context,
+	url, expectedDigest, filePath string,
+	timeout time.Duration) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{Timeout: timeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxInputsFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download artifact: %w", err)
+	}
+	if len(data) > maxInputsFileSize {
+		return nil, fmt.Errorf("artifact size exceeds the limit of %d bytes", maxInputsFileSize)
+	}
+
+	if expectedDigest != "" {
+		d, err := digest.Parse(expectedDigest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact digest '%s': %w", expectedDigest, err)
+		}
+		if actual := d.Algorithm().FromBytes(data); actual != d {
+			return nil, fmt.Errorf("artifact digest mismatch: expected %s, got %s", d, actual)
+		}
+	}
+
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	defer gzr.Close()
+
+	target := path.Clean(strings.TrimPrefix(filePath, "/"))
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Clean(strings.TrimPrefix(hdr.Name, "/")) != target {
+			continue
+		}
+		return io.ReadAll(io.LimitReader(tr, maxInputsFileSize))
+	}
+
+	return nil, fmt.Errorf("file '%s' not found in artifact", filePath)
+}
+
+// watchSource watches the Flux sources of the given kind, to reconcile the
+// FluxArtifact providers referencing them when their artifact changes.
+// The watch is started only once per kind.
+func (r *ResourceSetInputProviderReconciler) watchSource(gvk schema.GroupVersionKind) error {
+	if r.selectorController == nil {
+		return nil
+	}
+
+	r.selectorMu.Lock()
+	defer r.selectorMu.Unlock()
+
+	if r.sourceKinds[gvk] {
+		return nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := r.selectorController.Watch(source.Kind[client.Object](r.selectorCache, obj,
+		handler.EnqueueRequestsFromMapFunc(r.requestsForSource),
+		predicate.Funcs{UpdateFunc: artifactChanged})); err != nil {
+		return err
+	}
+
+	r.sourceKinds[gvk] = true
+	return nil
+}
+
+// requestsForSource returns the reconcile requests of the
+// FluxArtifact providers referencing the Flux source.
+func (r *ResourceSetInputProviderReconciler) requestsForSource(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := ctrl.LoggerFrom(ctx)
+
+	var list fluxcdv1.ResourceSetInputProviderList
+	if err := r.List(ctx, &list,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{
+			sourceRefIndexKey: fmt.Sprintf("%s/%s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName()),
+		}); err != nil {
+		log.Error(err, "failed to list objects for source change")
+		return nil
+	}
+
+	reqs := make([]reconcile.Request, len(list.Items))
+	for i := range list.Items {
+		reqs[i].NamespacedName = types.NamespacedName{Name: list.Items[i].Name, Namespace: list.Items[i].Namespace}
+	}
+	return reqs
+}
+
+// artifactChanged returns true if the revision or
+// the digest of the Flux source artifact changed.
+func artifactChanged(e event.UpdateEvent) bool {
+	oldObj, okOld := e.ObjectOld.(*unstructured.Unstructured)
+	newObj, okNew := e.ObjectNew.(*unstructured.Unstructured)
+	if !okOld || !okNew {
+		return false
+	}
+
+	for _, field := range []string{"revision", "digest"} {
+		oldValue, _, _ := unstructured.NestedString(oldObj.Object, "status", "artifact", field)
+		newValue, _, _ := unstructured.NestedString(newObj.Object, "status", "artifact", field)
+		if oldValue != newValue {
+			return true
+		}
+	}
+	return false
+}
+
+// indexBySourceRef indexes the ResourceSetInputProviders
+// of type FluxArtifact by the source kind and name.
+func (r *ResourceSetInputProviderReconciler) indexBySourceRef(o client.Object) []string {
+	rsip, ok := o.(*fluxcdv1.ResourceSetInputProvider)
+	if !ok {
+		return nil
+	}
+
+	if rsip.Spec.Type != fluxcdv1.InputProviderFluxArtifact || rsip.Spec.SourceRef == nil {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%s/%s", rsip.Spec.SourceRef.Kind, rsip.Spec.SourceRef.Name)}
+}
+
+// parseInputsFile parses the YAML or JSON list of objects from the file
+// content and returns an input set for each object. The 'id' input is
+// computed as the Adler-32 checksum of the object's 'id' field, or of
+// the whole object when the field is not set.
+func parseInputsFile(obj *fluxcdv1.ResourceSetInputProvider, content []byte) ([]fluxcdv1.ResourceSetInput, error) {
+	var items []map[string]any
+	if err := yaml.Unmarshal(content, &items); err != nil {
+		return nil, fmt.Errorf("the file '%s' must contain a list of objects: %w", obj.Spec.File.Path, err)
+	}
+
+	limit := 100
+	if obj.Spec.Filter != nil && obj.Spec.Filter.Limit > 0 {
+		limit = obj.Spec.Filter.Limit
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	results := make([]map[string]any, 0, len(items))
+	for i, item := range items {
+		if item == nil {
+			return nil, fmt.Errorf("item[%d] in '%s' must be an object", i, obj.Spec.File.Path)
+		}
+
+		var id []byte
+		switch v := item["id"].(type) {
+		case string:
+			id = []byte(v)
+		case nil:
+			b, err := json.Marshal(item)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal item[%d]: %w", i, err)
+			}
+			id = b
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal item[%d] id: %w", i, err)
+			}
+			id = b
+		}
+		item["id"] = checksum(id)
+
+		results = append(results, item)
+	}
+
+	return newResourceSetInputs(obj, results)
+}
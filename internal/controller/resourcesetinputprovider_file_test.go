@@ -0,0 +1,239 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+func TestParseInputsFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		filter   *fluxcdv1.ResourceSetInputFilter
+		expected []map[string]string
+		err      string
+	}{
+		{
+			name: "YAML list with ids",
+			content: `
+- id: staging
+  replicas: 1
+- id: production
+  replicas: 3
+`,
+			expected: []map[string]string{
+				{"id": `"199426798"`, "replicas": `1`},
+				{"id": `"396624968"`, "replicas": `3`},
+			},
+		},
+		{
+			name:    "JSON list without ids",
+			content: `[{"tenant":"team1"}]`,
+			expected: []map[string]string{
+				{"id": `"1008666141"`, "tenant": `"team1"`},
+			},
+		},
+		{
+			name:    "limit",
+			content: `[{"id":1},{"id":2}]`,
+			filter:  &fluxcdv1.ResourceSetInputFilter{Limit: 1},
+			expected: []map[string]string{
+				{"id": `"3276850"`},
+			},
+		},
+		{
+			name:    "not a list",
+			content: `tenant: team1`,
+			err:     "must contain a list of objects",
+		},
+		{
+			name:    "list of scalars",
+			content: `[team1, team2]`,
+			err:     "must contain a list of objects",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &fluxcdv1.ResourceSetInputProvider{
+				Spec: fluxcdv1.ResourceSetInputProviderSpec{
+					Type:   fluxcdv1.InputProviderFluxArtifact,
+					File:   &fluxcdv1.ResourceSetInputFile{Path: "inputs.yaml"},
+					Filter: tt.filter,
+				},
+			}
+
+			inputs, err := parseInputsFile(obj, []byte(tt.content))
+			if tt.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(inputs).To(HaveLen(len(tt.expected)))
+			for i, input := range inputs {
+				g.Expect(input).To(HaveLen(len(tt.expected[i])))
+				for k, v := range tt.expected[i] {
+					g.Expect(input).To(HaveKey(k))
+					g.Expect(string(input[k].Raw)).To(Equal(v), "input[%d].%s", i, k)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchArtifactFile(t *testing.T) {
+	g := NewWithT(t)
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for name, content := range map[string]string{
+		"README.md":         "# inputs",
+		"envs/inputs.yaml":  "- id: staging\n",
+		"envs/ignored.yaml": "- id: ignored\n",
+	} {
+		g.Expect(tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})).To(Succeed())
+		_, err := tw.Write([]byte(content))
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(tw.Close()).To(Succeed())
+	g.Expect(gzw.Close()).To(Succeed())
+	artifact := buf.Bytes()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gitrepository/apps/slow/latest.tar.gz" {
+			<-r.Context().Done()
+			return
+		}
+		if r.URL.Path != "/gitrepository/apps/inputs/latest.tar.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(artifact)
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name     string
+		path     string
+		url      string
+		digest   string
+		timeout  time.Duration
+		expected string
+		err      string
+	}{
+		{
+			name:     "reads file",
+			path:     "envs/inputs.yaml",
+			digest:   digest.FromBytes(artifact).String(),
+			expected: "- id: staging\n",
+		},
+		{
+			name:     "reads file with leading slash",
+			path:     "/envs/../envs/inputs.yaml",
+			expected: "- id: staging\n",
+		},
+		{
+			name:   "digest mismatch",
+			path:   "envs/inputs.yaml",
+			digest: digest.FromString("other").String(),
+			err:    "artifact digest mismatch",
+		},
+		{
+			name: "file not found",
+			path: "inputs.yaml",
+			err:  "file 'inputs.yaml' not found in artifact",
+		},
+		{
+			name: "artifact not found",
+			path: "envs/inputs.yaml",
+			url:  srv.URL + "/gitrepository/apps/unknown/latest.tar.gz",
+			err:  "404 Not Found",
+		},
+		{
+			name:    "artifact download timeout",
+			path:    "envs/inputs.yaml",
+			url:     srv.URL + "/gitrepository/apps/slow/latest.tar.gz",
+			timeout: 100 * time.Millisecond,
+			err:     "Client.Timeout exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			url := tt.url
+			if url == "" {
+				url = srv.URL + "/gitrepository/apps/inputs/latest.tar.gz"
+			}
+
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = time.Minute
+			}
+
+			content, err := fetchArtifactFile(context.Background(), url, tt.digest, tt.path, timeout)
+			if tt.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(content)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestArtifactChanged(t *testing.T) {
+	g := NewWithT(t)
+
+	newSource := func(revision, message string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"status": map[string]any{
+				"artifact": map[string]any{
+					"revision": revision,
+				},
+				"conditions": []any{
+					map[string]any{"type": "Ready", "message": message},
+				},
+			},
+		}}
+	}
+
+	g.Expect(artifactChanged(event.UpdateEvent{
+		ObjectOld: newSource("main@sha1:a1", "stored artifact"),
+		ObjectNew: newSource("main@sha1:a2", "stored artifact"),
+	})).To(BeTrue())
+
+	g.Expect(artifactChanged(event.UpdateEvent{
+		ObjectOld: newSource("main@sha1:a1", "stored artifact"),
+		ObjectNew: newSource("main@sha1:a1", "no changes since last reconciliation"),
+	})).To(BeFalse())
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+// maxExportHistory is the maximum number of
+// export revisions recorded in the object status.
+const maxExportHistory = 10
+
+// inputsDiff holds the ids of the input sets that were
+// added, updated or removed between two exports.
+type inputsDiff struct {
+	added   []string
+	updated []string
+	removed []string
+}
+
+// diffInputs compares the previous and current exported inputs by id.
+// An input set is considered updated if its 'sha' field has changed,
+// or if any of its fields has changed when the input set has no 'sha'.
+func diffInputs(previous, current []fluxcdv1.ResourceSetInput) inputsDiff {
+	prev := indexInputsByID(previous)
+	curr := indexInputsByID(current)
+
+	var diff inputsDiff
+	for id, input := range curr {
+		old, ok := prev[id]
+		switch {
+		case !ok:
+			diff.added = append(diff.added, id)
+		case inputChanged(old, input):
+			diff.updated = append(diff.updated, id)
+		}
+	}
+	for id := range prev {
+		if _, ok := curr[id]; !ok {
+			diff.removed = append(diff.removed, id)
+		}
+	}
+
+	sort.Strings(diff.added)
+	sort.Strings(diff.updated)
+	sort.Strings(diff.removed)
+	return diff
+}
+
+// isEmpty returns true if no input sets were added, updated or removed.
+func (d inputsDiff) isEmpty() bool {
+	return len(d.added) == 0 && len(d.updated) == 0 && len(d.removed) == 0
+}
+
+// String returns a message listing the ids of the changed input sets.
+func (d inputsDiff) String() string {
+	var parts []string
+	if len(d.added) > 0 {
+		parts = append(parts, fmt.Sprintf("added [%s]", strings.Join(d.added, ", ")))
+	}
+	if len(d.updated) > 0 {
+		parts = append(parts, fmt.Sprintf("updated [%s]", strings.Join(d.updated, ", ")))
+	}
+	if len(d.removed) > 0 {
+		parts = append(parts, fmt.Sprintf("removed [%s]", strings.Join(d.removed, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// recordExportHistory prepends the export revision to the object
+// status history and drops the oldest entries over the limit.
+func recordExportHistory(obj *fluxcdv1.ResourceSetInputProvider,
+	revision string, diff inputsDiff, exportedAt time.Time) {
+	entry := fluxcdv1.InputsExport{
+		Revision:   revision,
+		ExportedAt: metav1.NewTime(exportedAt),
+		Added:      diff.added,
+		Updated:    diff.updated,
+		Removed:    diff.removed,
+	}
+
+	history := append([]fluxcdv1.InputsExport{entry}, obj.Status.ExportHistory...)
+	if len(history) > maxExportHistory {
+		history = history[:maxExportHistory]
+	}
+	obj.Status.ExportHistory = history
+}
+
+// indexInputsByID returns the input sets indexed by their 'id' field.
+// The input sets without an id are ignored.
+func indexInputsByID(inputs []fluxcdv1.ResourceSetInput) map[string]fluxcdv1.ResourceSetInput {
+	index := make(map[string]fluxcdv1.ResourceSetInput, len(inputs))
+	for _, input := range inputs {
+		if id, ok := inputID(input); ok {
+			index[id] = input
+		}
+	}
+	return index
+}
+
+// inputChanged returns true if the 'sha' field of the input set has changed,
+// or if any field has changed when neither input set has a 'sha'.
+func inputChanged(old, current fluxcdv1.ResourceSetInput) bool {
+	oldSHA, oldOK := old["sha"]
+	currSHA, currOK := current["sha"]
+	if oldOK || currOK {
+		return !oldOK || !currOK || !bytes.Equal(rawJSON(oldSHA), rawJSON(currSHA))
+	}
+
+	if len(old) != len(current) {
+		return true
+	}
+	for k, v := range current {
+		o, ok := old[k]
+		if !ok || !bytes.Equal(rawJSON(o), rawJSON(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// rawJSON returns the raw bytes of the JSON value, or nil if the value is not set.
+func rawJSON(v *apiextensionsv1.JSON) []byte {
+	if v == nil {
+		return nil
+	}
+	return v.Raw
+}
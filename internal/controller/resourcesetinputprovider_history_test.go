@@ -0,0 +1,126 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+func TestDiffInputs(t *testing.T) {
+	input := func(fields map[string]string) fluxcdv1.ResourceSetInput {
+		in := make(fluxcdv1.ResourceSetInput, len(fields))
+		for k, v := range fields {
+			in[k] = &apiextensionsv1.JSON{Raw: []byte(v)}
+		}
+		return in
+	}
+
+	tests := []struct {
+		name     string
+		previous []fluxcdv1.ResourceSetInput
+		current  []fluxcdv1.ResourceSetInput
+		expected inputsDiff
+		message  string
+	}{
+		{
+			name:     "first export",
+			previous: nil,
+			current: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"2"`, "sha": `"a"`}),
+				input(map[string]string{"id": `"1"`, "sha": `"b"`}),
+			},
+			expected: inputsDiff{added: []string{"1", "2"}},
+			message:  "added [1, 2]",
+		},
+		{
+			name: "sha changed",
+			previous: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "sha": `"a"`, "title": `"old"`}),
+				input(map[string]string{"id": `"2"`, "sha": `"b"`, "title": `"old"`}),
+			},
+			current: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "sha": `"c"`, "title": `"old"`}),
+				input(map[string]string{"id": `"2"`, "sha": `"b"`, "title": `"new"`}),
+			},
+			expected: inputsDiff{updated: []string{"1"}},
+			message:  "updated [1]",
+		},
+		{
+			name: "fields changed without sha",
+			previous: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `1`, "tag": `"v1.0.0"`}),
+			},
+			current: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `1`, "tag": `"v1.1.0"`}),
+			},
+			expected: inputsDiff{updated: []string{"1"}},
+			message:  "updated [1]",
+		},
+		{
+			name: "added, updated and removed",
+			previous: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "sha": `"a"`}),
+				input(map[string]string{"id": `"2"`, "sha": `"b"`}),
+			},
+			current: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"2"`, "sha": `"c"`}),
+				input(map[string]string{"id": `"3"`, "sha": `"d"`}),
+			},
+			expected: inputsDiff{
+				added:   []string{"3"},
+				updated: []string{"2"},
+				removed: []string{"1"},
+			},
+			message: "added [3], updated [2], removed [1]",
+		},
+		{
+			name: "unchanged",
+			previous: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "sha": `"a"`}),
+			},
+			current: []fluxcdv1.ResourceSetInput{
+				input(map[string]string{"id": `"1"`, "sha": `"a"`}),
+			},
+			expected: inputsDiff{},
+			message:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			diff := diffInputs(tt.previous, tt.current)
+			g.Expect(diff).To(Equal(tt.expected))
+			g.Expect(diff.isEmpty()).To(Equal(tt.message == ""))
+			g.Expect(diff.String()).To(Equal(tt.message))
+		})
+	}
+}
+
+func TestRecordExportHistory(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &fluxcdv1.ResourceSetInputProvider{}
+	start := time.Now()
+	for i := range maxExportHistory + 2 {
+		recordExportHistory(obj, fmt.Sprintf("rev%d", i),
+			inputsDiff{added: []string{fmt.Sprintf("%d", i)}},
+			start.Add(time.Duration(i)*time.Minute))
+	}
+
+	g.Expect(obj.Status.ExportHistory).To(HaveLen(maxExportHistory))
+	g.Expect(obj.Status.ExportHistory[0].Revision).To(Equal(fmt.Sprintf("rev%d", maxExportHistory+1)))
+	g.Expect(obj.Status.ExportHistory[0].Added).To(Equal([]string{fmt.Sprintf("%d", maxExportHistory+1)}))
+	g.Expect(obj.Status.ExportHistory[maxExportHistory-1].Revision).To(Equal("rev2"))
+	g.Expect(obj.Status.ExportHistory[0].ExportedAt.After(
+		obj.Status.ExportHistory[1].ExportedAt.Time)).To(BeTrue())
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+// maxHTTPResponseSize is the maximum size of the HTTP JSON response body.
+const maxHTTPResponseSize = 10 << 20
+
+// callHTTPJSON fetches the JSON document from the provider URL, extracts the
+// list of items and returns an input set for each item. When the username is
+// empty in the credentials secret, the password is used as a bearer token.
+func (r *ResourceSetInputProviderReconciler) callHTTPJSON(ctx context.Context,
+	obj *fluxcdv1.ResourceSetInputProvider,
+	certPool *x509.CertPool,
+	authData map[string][]byte) ([]fluxcdv1.ResourceSetInput, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, obj.Spec.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	if authData != nil {
+		username, password, err := r.getBasicAuth(obj, authData)
+		if err != nil {
+			return nil, err
+		}
+		if username != "" {
+			req.SetBasicAuth(username, password)
+		} else if password != "" {
+			req.Header.Set("Authorization", "Bearer "+password)
+		}
+	}
+
+	httpClient := &http.Client{}
+	if certPool != nil {
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = &tls.Config{
+			RootCAs: certPool,
+		}
+		httpClient.Transport = tr
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GET %s: %s %s", obj.Spec.URL, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var doc any
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxHTTPResponseSize)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON response: %w", err)
+	}
+
+	return extractHTTPJSONInputs(ctx, obj, doc)
+}
+
+// extractHTTPJSONInputs extracts the list of items from the JSON document and
+// maps the fields of each item into an input set. The 'id' input is computed
+// as the Adler-32 checksum of the item ID, or of the whole item when the ID
+// expression is not set.
+func extractHTTPJSONInputs(ctx context.Context,
+	obj *fluxcdv1.ResourceSetInputProvider,
+	doc any) ([]fluxcdv1.ResourceSetInput, error) {
+	spec := obj.Spec.JSON
+	if spec == nil {
+		spec = &fluxcdv1.ResourceSetInputJSON{}
+	}
+
+	var items []any
+	switch {
+	case spec.ItemsExpr != "":
+		result, err := evaluateCEL(ctx, spec.ItemsExpr, map[string]any{"response": doc})
+		if err != nil {
+			return nil, err
+		}
+		list, ok := result.([]any)
+		if !ok {
+			return nil, fmt.Errorf("the CEL expression '%s' must return a list", spec.ItemsExpr)
+		}
+		items = list
+	case spec.Items != "":
+		values, err := jsonPathResults(doc, spec.Items)
+		if err != nil {
+			return nil, fmt.Errorf("invalid items JSONPath: %w", err)
+		}
+		// Unwrap the list when the expression selects the array itself.
+		if len(values) == 1 {
+			if list, ok := values[0].([]any); ok {
+				values = list
+			}
+		}
+		items = values
+	default:
+		list, ok := doc.([]any)
+		if !ok {
+			return nil, errors.New("the JSON response must be an array when the items expression is not set")
+		}
+		items = list
+	}
+
+	limit := 100
+	if obj.Spec.Filter != nil && obj.Spec.Filter.Limit > 0 {
+		limit = obj.Spec.Filter.Limit
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	results := make([]map[string]any, 0, len(items))
+	for i, item := range items {
+		values := make(map[string]any)
+		switch {
+		case len(spec.Fields) > 0:
+			for key, expr := range spec.Fields {
+				value, found, err := jsonPathValue(item, expr)
+				if err != nil {
+					return nil, fmt.Errorf("invalid field '%s': %w", key, err)
+				}
+				if found {
+					values[key] = value
+				}
+			}
+		default:
+			if m, ok := item.(map[string]any); ok {
+				for k, v := range m {
+					values[k] = v
+				}
+			} else {
+				values["value"] = item
+			}
+		}
+
+		idSource := item
+		if spec.ID != "" {
+			value, found, err := jsonPathValue(item, spec.ID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid id JSONPath: %w", err)
+			}
+			if !found {
+				return nil, fmt.Errorf("item[%d] has no id at '%s'", i, spec.ID)
+			}
+			idSource = value
+		}
+
+		var id []byte
+		if s, ok := idSource.(string); ok {
+			id = []byte(s)
+		} else {
+			b, err := json.Marshal(idSource)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal item[%d] id: %w", i, err)
+			}
+			id = b
+		}
+		values["id"] = checksum(id)
+
+		results = append(results, values)
+	}
+
+	return newResourceSetInputs(obj, results)
+}
+
+// evaluateCEL evaluates the CEL expression with the given variables
+// and returns the result converted to JSON compatible values.
+// The Flux runtime CEL expressions return only scalar values,
+// while the items expression returns a list of objects.
+func evaluateCEL(ctx context.Context, expr string, vars map[string]any) (any, error) {
+	envOpts := []cel.EnvOption{
+		cel.HomogeneousAggregateLiterals(),
+		cel.EagerlyValidateDeclarations(true),
+		cel.DefaultUTCTimeZone(true),
+		cel.CrossTypeNumericComparisons(true),
+		cel.OptionalTypes(),
+		ext.Strings(),
+		ext.Sets(),
+		ext.Encoders(),
+	}
+	for name := range vars {
+		envOpts = append(envOpts, cel.Variable(name, cel.DynType))
+	}
+
+	env, err := cel.NewEnv(envOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("failed to parse the CEL expression '%s': %s", expr, issues.String())
+	}
+
+	prog, err := env.Program(ast, cel.InterruptCheckFrequency(100))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL program: %w", err)
+	}
+
+	val, _, err := prog.ContextEval(ctx, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate the CEL expression '%s': %w", expr, err)
+	}
+
+	native, err := val.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the CEL expression '%s' result: %w", expr, err)
+	}
+
+	data, err := protojson.Marshal(native.(*structpb.Value))
+	if err != nil {
+		return nil, err
+	}
+
+	var result any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
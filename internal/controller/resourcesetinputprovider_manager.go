@@ -4,6 +4,10 @@
 package controller
 
 import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -20,8 +24,17 @@ type ResourceSetInputProviderReconcilerOptions struct {
 }
 
 // SetupWithManager sets up the controller with the Manager.
-func (r *ResourceSetInputProviderReconciler) SetupWithManager(mgr ctrl.Manager, opts ResourceSetInputProviderReconcilerOptions) error {
-	return ctrl.NewControllerManagedBy(mgr).
+func (r *ResourceSetInputProviderReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opts ResourceSetInputProviderReconcilerOptions) error {
+	if err := mgr.GetCache().IndexField(ctx, &fluxcdv1.ResourceSetInputProvider{}, selectorKindIndexKey,
+		r.indexBySelectedKind); err != nil {
+		return fmt.Errorf("failed setting index fields: %w", err)
+	}
+	if err := mgr.GetCache().IndexField(ctx, &fluxcdv1.ResourceSetInputProvider{}, sourceRefIndexKey,
+		r.indexBySourceRef); err != nil {
+		return fmt.Errorf("failed setting index fields: %w", err)
+	}
+
+	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&fluxcdv1.ResourceSetInputProvider{},
 			builder.WithPredicates(
 				predicate.Or(
@@ -31,5 +44,17 @@ func (r *ResourceSetInputProviderReconciler) SetupWithManager(mgr ctrl.Manager,
 			)).
 		WithOptions(controller.Options{
 			RateLimiter: opts.RateLimiter,
-		}).Complete(r)
+		}).Build(r)
+	if err != nil {
+		return err
+	}
+
+	// Store the controller to add watches for the kinds selected by the
+	// KubernetesSelector providers and for the FluxArtifact sources.
+	r.selectorController = c
+	r.selectorCache = mgr.GetCache()
+	r.selectorKinds = make(map[schema.GroupVersionKind]bool)
+	r.sourceKinds = make(map[schema.GroupVersionKind]bool)
+
+	return nil
 }
@@ -0,0 +1,84 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/gitprovider"
+)
+
+// mergeResults lists the results of the providers concurrently and sets the
+// repository identity of each result. It returns the merged results sorted and
+// truncated to the limit, and the number of results dropped by the limit.
+// It fails if more than one URL points to the same repository, the repository
+// paths are compared case-insensitively as in the GitHub and GitLab APIs.
+func (r *ResourceSetInputProviderReconciler) mergeResults(ctx context.Context,
+	obj *fluxcdv1.ResourceSetInputProvider,
+	opts gitprovider.Options,
+	providers []gitprovider.Interface) ([]gitprovider.Result, int, error) {
+	paths := make([]string, len(providers))
+	for i, provider := range providers {
+		ri, ok := provider.(gitprovider.RepositoryIdentifier)
+		if !ok {
+			return nil, 0, fmt.Errorf("multiple URLs are not supported for type %s", obj.Spec.Type)
+		}
+		paths[i] = ri.RepositoryPath()
+		if j := slices.IndexFunc(paths[:i], func(p string) bool {
+			return strings.EqualFold(p, paths[i])
+		}); j >= 0 {
+			return nil, 0, fmt.Errorf("spec.urls[%d] and spec.urls[%d] point to the same repository %s", j, i, paths[i])
+		}
+	}
+
+	lists := make([][]gitprovider.Result, len(providers))
+	errs := make([]error, len(providers))
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results, err := r.listResults(ctx, obj, opts, provider)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", paths[i], err)
+				return
+			}
+			for j := range results {
+				results[j].SetRepository(paths[i])
+			}
+			lists[i] = results
+		}()
+	}
+	wg.Wait()
+
+	if err := kerrors.NewAggregate(errs); err != nil {
+		return nil, 0, err
+	}
+
+	var merged []gitprovider.Result
+	var dropped int
+	for i, provider := range providers {
+		merged = append(merged, lists[i]...)
+		dropped += droppedResults(provider)
+	}
+
+	merged, n := gitprovider.LimitResults(opts, merged)
+	return merged, dropped + n, nil
+}
+
+// droppedResults returns the number of results dropped by the limit in the
+// last call of the provider, or zero if the provider doesn't report it.
+func droppedResults(provider gitprovider.Interface) int {
+	if lr, ok := provider.(gitprovider.LimitReporter); ok {
+		return lr.Dropped()
+	}
+	return 0
+}
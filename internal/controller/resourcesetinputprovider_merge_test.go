@@ -0,0 +1,212 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+	. "github.com/onsi/gomega"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+	"github.com/controlplaneio-fluxcd/flux-operator/internal/gitprovider"
+)
+
+type fakeRepositoryProvider struct {
+	path    string
+	results []gitprovider.Result
+	err     error
+	dropped int
+	limit   *gitprovider.RateLimit
+}
+
+func (f *fakeRepositoryProvider) ListBranches(context.Context, gitprovider.Options) ([]gitprovider.Result, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeRepositoryProvider) ListRequests(_ context.Context, opts gitprovider.Options) ([]gitprovider.Result, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	results := append([]gitprovider.Result(nil), f.results...)
+	results, f.dropped = gitprovider.LimitResults(opts, results)
+	return results, nil
+}
+
+func (f *fakeRepositoryProvider) ListTags(context.Context, gitprovider.Options) ([]gitprovider.Result, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeRepositoryProvider) RepositoryPath() string {
+	return f.path
+}
+
+func (f *fakeRepositoryProvider) Dropped() int {
+	return f.dropped
+}
+
+func (f *fakeRepositoryProvider) RateLimit() *gitprovider.RateLimit {
+	return f.limit
+}
+
+func TestMergeResults(t *testing.T) {
+	pr := func(number int, createdAt string) gitprovider.Result {
+		return gitprovider.Result{ID: fmt.Sprint(number), Number: number, CreatedAt: createdAt}
+	}
+	newProviders := func(err error, apiPath string) []gitprovider.Interface {
+		return []gitprovider.Interface{
+			&fakeRepositoryProvider{
+				path: "org/app",
+				results: []gitprovider.Result{
+					pr(1, "2025-01-01T00:00:00Z"),
+					pr(2, "2025-01-04T00:00:00Z"),
+					pr(3, "2025-01-06T00:00:00Z"),
+				},
+			},
+			&fakeRepositoryProvider{
+				path: apiPath,
+				results: []gitprovider.Result{
+					pr(1, "2025-01-05T00:00:00Z"),
+					pr(2, "2025-01-02T00:00:00Z"),
+				},
+				err: err,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		filters     gitprovider.Filters
+		err         error
+		apiPath     string
+		want        []string
+		wantDropped int
+		wantErr     string
+	}{
+		{
+			name: "merges in the URLs order",
+			want: []string{"org/app#1", "org/app#2", "org/app#3", "org/api#1", "org/api#2"},
+		},
+		{
+			name:        "sorts the merged results before the limit",
+			filters:     gitprovider.Filters{SortBy: gitprovider.SortByCreated, SortDescending: true, Limit: 3},
+			want:        []string{"org/app#3", "org/api#1", "org/app#2"},
+			wantDropped: 2,
+		},
+		{
+			name:    "fails if any repository fails",
+			err:     errors.New("not found"),
+			wantErr: "org/api: failed to list requests: not found",
+		},
+		{
+			name:    "fails if the URLs point to the same repository",
+			apiPath: "org/App",
+			wantErr: "spec.urls[0] and spec.urls[1] point to the same repository org/App",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &ResourceSetInputProviderReconciler{}
+			obj := &fluxcdv1.ResourceSetInputProvider{}
+			obj.Spec.Type = fluxcdv1.InputProviderGitHubPullRequest
+
+			apiPath := tt.apiPath
+			if apiPath == "" {
+				apiPath = "org/api"
+			}
+
+			results, dropped, err := r.mergeResults(context.Background(), obj,
+				gitprovider.Options{Filters: tt.filters}, newProviders(tt.err, apiPath))
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var got []string
+			ids := make(map[string]bool)
+			for _, res := range results {
+				got = append(got, fmt.Sprintf("%s#%d", res.RepositoryPath, res.Number))
+				g.Expect(res.Repository).To(Equal(path.Base(res.RepositoryPath)))
+				ids[res.ID] = true
+			}
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(ids).To(HaveLen(len(results)))
+			g.Expect(dropped).To(Equal(tt.wantDropped))
+		})
+	}
+}
+
+func TestRecordRateLimit(t *testing.T) {
+	retryAt := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+	urls := []string{"https://github.com/org/app", "https://github.com/org/api", "https://github.com/org/web"}
+
+	tests := []struct {
+		name       string
+		limits     []*gitprovider.RateLimit
+		wantReason string
+		wantMsg    string
+	}{
+		{
+			name:   "no rate limit reported",
+			limits: []*gitprovider.RateLimit{nil, nil, nil},
+		},
+		{
+			name: "lowest remaining quota",
+			limits: []*gitprovider.RateLimit{
+				{Limit: 5000, Remaining: 4000},
+				{Limit: 5000, Remaining: 10},
+				nil,
+			},
+			wantReason: fluxcdv1.RateLimitAvailableReason,
+			wantMsg:    "API rate limit remaining 10/5000",
+		},
+		{
+			name: "latest retry time of the exceeded rate limits",
+			limits: []*gitprovider.RateLimit{
+				{Remaining: 0, RetryAt: retryAt},
+				{Remaining: 0, RetryAt: retryAt.Add(time.Hour)},
+				{Remaining: 10},
+			},
+			wantReason: fluxcdv1.RateLimitExceededReason,
+			wantMsg:    "resumed at 2025-04-10T13:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &ResourceSetInputProviderReconciler{}
+			obj := &fluxcdv1.ResourceSetInputProvider{}
+			obj.SetName("test")
+			obj.SetNamespace("default")
+
+			providers := make([]gitprovider.Interface, len(tt.limits))
+			for i, limit := range tt.limits {
+				providers[i] = &fakeRepositoryProvider{limit: limit}
+			}
+
+			rateLimit := r.recordRateLimit(obj, urls, providers)
+			if tt.wantReason == "" {
+				g.Expect(rateLimit).To(BeNil())
+				g.Expect(conditions.Get(obj, fluxcdv1.RateLimitedCondition)).To(BeNil())
+				return
+			}
+
+			g.Expect(rateLimit).ToNot(BeNil())
+			g.Expect(conditions.GetReason(obj, fluxcdv1.RateLimitedCondition)).To(Equal(tt.wantReason))
+			g.Expect(conditions.GetMessage(obj, fluxcdv1.RateLimitedCondition)).To(ContainSubstring(tt.wantMsg))
+		})
+	}
+}
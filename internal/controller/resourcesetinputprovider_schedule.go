@@ -0,0 +1,80 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+// scheduleParser parses the standard five-field cron expressions
+// and the descriptors such as '@daily'.
+var scheduleParser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// validateSchedule returns an error if any of the schedule
+// windows has an invalid cron expression, time zone or duration.
+func validateSchedule(schedules []fluxcdv1.ResourceSetInputSchedule) error {
+	for i, s := range schedules {
+		if _, _, err := parseSchedule(i, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSchedule returns the cron schedule and the time zone of the schedule window.
+func parseSchedule(i int, s fluxcdv1.ResourceSetInputSchedule) (cron.Schedule, *time.Location, error) {
+	loc := time.UTC
+	if s.TimeZone != "" {
+		var err error
+		loc, err = time.LoadLocation(s.TimeZone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid schedule[%d] time zone '%s': %w", i, s.TimeZone, err)
+		}
+	}
+
+	sched, err := scheduleParser.Parse(s.Cron)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schedule[%d] cron '%s': %w", i, s.Cron, err)
+	}
+
+	if s.Window.Duration <= 0 {
+		return nil, nil, fmt.Errorf("invalid schedule[%d] window '%s': must be greater than zero", i, s.Window.Duration)
+	}
+
+	return sched, loc, nil
+}
+
+// checkSchedule returns true if the given time is within one of the schedule
+// windows. When all the windows are closed, it also returns the time when
+// the next window opens. An empty schedule is always open.
+func checkSchedule(schedules []fluxcdv1.ResourceSetInputSchedule, now time.Time) (bool, time.Time, error) {
+	var next time.Time
+	for i, s := range schedules {
+		sched, loc, err := parseSchedule(i, s)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+
+		// The window is open if a start time falls within the window duration
+		// before now. The cron library returns the zero time if the expression
+		// has no start time in the next five years.
+		localNow := now.In(loc)
+		if start := sched.Next(localNow.Add(-s.Window.Duration)); !start.IsZero() && !start.After(localNow) {
+			return true, time.Time{}, nil
+		}
+
+		if start := sched.Next(localNow); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+
+	return len(schedules) == 0, next, nil
+}
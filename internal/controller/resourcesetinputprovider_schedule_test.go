@@ -0,0 +1,124 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+func TestCheckSchedule(t *testing.T) {
+	businessHours := fluxcdv1.ResourceSetInputSchedule{
+		Cron:     "0 9 * * 1-5",
+		TimeZone: "Europe/Bucharest",
+		Window:   metav1.Duration{Duration: 8 * time.Hour},
+	}
+	// Wednesday, 2025-04-09 in UTC, Bucharest is UTC+3.
+	wednesday := func(hour, minute int) time.Time {
+		return time.Date(2025, 4, 9, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		schedules []fluxcdv1.ResourceSetInputSchedule
+		now       time.Time
+		open      bool
+		next      time.Time
+		err       string
+	}{
+		{
+			name: "no schedule",
+			now:  wednesday(3, 0),
+			open: true,
+		},
+		{
+			name:      "inside window",
+			schedules: []fluxcdv1.ResourceSetInputSchedule{businessHours},
+			now:       wednesday(6, 0),
+			open:      true,
+		},
+		{
+			name:      "window close is exclusive",
+			schedules: []fluxcdv1.ResourceSetInputSchedule{businessHours},
+			now:       wednesday(14, 0),
+			open:      false,
+			next:      time.Date(2025, 4, 10, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "before window",
+			schedules: []fluxcdv1.ResourceSetInputSchedule{businessHours},
+			now:       wednesday(5, 59),
+			open:      false,
+			next:      wednesday(6, 0),
+		},
+		{
+			name:      "weekend",
+			schedules: []fluxcdv1.ResourceSetInputSchedule{businessHours},
+			now:       time.Date(2025, 4, 12, 10, 0, 0, 0, time.UTC),
+			open:      false,
+			next:      time.Date(2025, 4, 14, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "earliest next window",
+			schedules: []fluxcdv1.ResourceSetInputSchedule{
+				businessHours,
+				{Cron: "@daily", Window: metav1.Duration{Duration: time.Hour}},
+			},
+			now:  wednesday(20, 0),
+			open: false,
+			next: time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "any window open",
+			schedules: []fluxcdv1.ResourceSetInputSchedule{
+				businessHours,
+				{Cron: "@daily", Window: metav1.Duration{Duration: time.Hour}},
+			},
+			now:  wednesday(0, 30),
+			open: true,
+		},
+		{
+			name:      "invalid cron",
+			schedules: []fluxcdv1.ResourceSetInputSchedule{{Cron: "0 9 * *", Window: businessHours.Window}},
+			now:       wednesday(6, 0),
+			err:       "invalid schedule[0] cron",
+		},
+		{
+			name: "invalid time zone",
+			schedules: []fluxcdv1.ResourceSetInputSchedule{
+				{Cron: "0 9 * * *", TimeZone: "Mars/Olympus", Window: businessHours.Window},
+			},
+			now: wednesday(6, 0),
+			err: "invalid schedule[0] time zone",
+		},
+		{
+			name:      "invalid window",
+			schedules: []fluxcdv1.ResourceSetInputSchedule{{Cron: "0 9 * * *"}},
+			now:       wednesday(6, 0),
+			err:       "invalid schedule[0] window",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			open, next, err := checkSchedule(tt.schedules, tt.now)
+			if tt.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(open).To(Equal(tt.open))
+			g.Expect(next.Equal(tt.next)).To(BeTrue(), "expected next window %s, got %s", tt.next, next)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+const selectorKindIndexKey string = ".spec.selector.kind"
+
+// selectObjects lists the Kubernetes objects that match the selector
+// and returns an input set for each object. The objects of namespaced
+// kinds are selected from the ResourceSetInputProvider namespace.
+func (r *ResourceSetInputProviderReconciler) selectObjects(ctx context.Context,
+	obj *fluxcdv1.ResourceSetInputProvider) ([]fluxcdv1.ResourceSetInput, error) {
+	sel := obj.Spec.Selector
+	if sel == nil {
+		return nil, errors.New("selector is required for the KubernetesSelector type")
+	}
+
+	gvk := schema.FromAPIVersionAndKind(sel.APIVersion, sel.Kind)
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	var listOpts []client.ListOption
+	namespaced, err := r.IsObjectNamespaced(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the scope of %s: %w", gvk.Kind, err)
+	}
+	if namespaced {
+		listOpts = append(listOpts, client.InNamespace(obj.GetNamespace()))
+	}
+	if sel.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(sel.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector: %w", err)
+		}
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	if err := r.List(ctx, list, listOpts...); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+	}
+
+	// Watch the selected kind after listing the objects
+	// to ensure the kind is served by the API server.
+	if err := r.watchSelectedKind(gvk); err != nil {
+		return nil, fmt.Errorf("failed to watch %s: %w", gvk.Kind, err)
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return client.ObjectKeyFromObject(&list.Items[i]).String() <
+			client.ObjectKeyFromObject(&list.Items[j]).String()
+	})
+
+	limit := 100
+	if obj.Spec.Filter != nil && obj.Spec.Filter.Limit > 0 {
+		limit = obj.Spec.Filter.Limit
+	}
+	if len(list.Items) > limit {
+		list.Items = list.Items[:limit]
+	}
+
+	items := make([]map[string]any, 0, len(list.Items))
+	for _, item := range list.Items {
+		values := map[string]any{
+			"id":   checksum([]byte(client.ObjectKeyFromObject(&item).String())),
+			"name": item.GetName(),
+		}
+		if item.GetNamespace() != "" {
+			values["namespace"] = item.GetNamespace()
+		}
+
+		for key, expr := range sel.Fields {
+			value, found, err := jsonPathValue(item.Object, expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid field '%s': %w", key, err)
+			}
+			if found {
+				values[key] = value
+			}
+		}
+
+		items = append(items, values)
+	}
+
+	return newResourceSetInputs(obj, items)
+}
+
+// newResourceSetInputs converts the given values into
+// ResourceSet inputs with the provider default values.
+func newResourceSetInputs(obj *fluxcdv1.ResourceSetInputProvider,
+	items []map[string]any) ([]fluxcdv1.ResourceSetInput, error) {
+	defaults, err := obj.GetDefaultInputs()
+	if err != nil {
+		return nil, fmt.Errorf("invalid default values: %w", err)
+	}
+
+	inputs := make([]fluxcdv1.ResourceSetInput, 0, len(items))
+	for _, values := range items {
+		input := make(fluxcdv1.ResourceSetInput, len(values)+len(defaults))
+		for k, v := range defaults {
+			if _, ok := values[k]; !ok {
+				values[k] = v
+			}
+		}
+		for k, v := range values {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal value to JSON %v: %w", v, err)
+			}
+			input[k] = &apiextensionsv1.JSON{Raw: b}
+		}
+		inputs = append(inputs, input)
+	}
+
+	return inputs, nil
+}
+
+// jsonPathResults evaluates the JSONPath expression against
+// the object and returns the list of values found.
+func jsonPathResults(obj any, expr string) ([]any, error) {
+	if !strings.HasPrefix(expr, "{") {
+		expr = fmt.Sprintf("{%s}", expr)
+	}
+
+	jp := jsonpath.New("field").AllowMissingKeys(true)
+	if err := jp.Parse(expr); err != nil {
+		return nil, err
+	}
+
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []any
+	for _, result := range results {
+		for _, v := range result {
+			values = append(values, v.Interface())
+		}
+	}
+
+	return values, nil
+}
+
+// jsonPathValue evaluates the JSONPath expression against the object and returns
+// the value found. When the expression matches multiple values, a list is returned.
+func jsonPathValue(obj any, expr string) (any, bool, error) {
+	values, err := jsonPathResults(obj, expr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch len(values) {
+	case 0:
+		return nil, false, nil
+	case 1:
+		return values[0], true, nil
+	default:
+		return values, true, nil
+	}
+}
+
+// watchSelectedKind watches the metadata of the objects of the given kind,
+// to reconcile the ResourceSetInputProviders selecting them on changes.
+// The watch is started only once per kind.
+func (r *ResourceSetInputProviderReconciler) watchSelectedKind(gvk schema.GroupVersionKind) error {
+	if r.selectorController == nil {
+		return nil
+	}
+
+	r.selectorMu.Lock()
+	defer r.selectorMu.Unlock()
+
+	if r.selectorKinds[gvk] {
+		return nil
+	}
+
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	if err := r.selectorController.Watch(source.Kind[client.Object](r.selectorCache, obj,
+		handler.EnqueueRequestsFromMapFunc(r.requestsForSelectedObject(gvk)))); err != nil {
+		return err
+	}
+
+	r.selectorKinds[gvk] = true
+	return nil
+}
+
+// requestsForSelectedObject returns the reconcile requests of the
+// ResourceSetInputProviders that select the changed object.
+func (r *ResourceSetInputProviderReconciler) requestsForSelectedObject(gvk schema.GroupVersionKind) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		log := ctrl.LoggerFrom(ctx)
+
+		var list fluxcdv1.ResourceSetInputProviderList
+		if err := r.List(ctx, &list, client.MatchingFields{
+			selectorKindIndexKey: gvk.String(),
+		}); err != nil {
+			log.Error(err, "failed to list objects for selected object change")
+			return nil
+		}
+
+		var reqs []reconcile.Request
+		for _, rsip := range list.Items {
+			if obj.GetNamespace() != "" && obj.GetNamespace() != rsip.GetNamespace() {
+				continue
+			}
+
+			if rsip.Spec.Selector.LabelSelector != nil {
+				selector, err := metav1.LabelSelectorAsSelector(rsip.Spec.Selector.LabelSelector)
+				if err != nil || !selector.Matches(labels.Set(obj.GetLabels())) {
+					continue
+				}
+			}
+
+			reqs = append(reqs, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: rsip.Name, Namespace: rsip.Namespace},
+			})
+		}
+
+		return reqs
+	}
+}
+
+// indexBySelectedKind indexes the ResourceSetInputProviders
+// of type KubernetesSelector by the selected kind.
+func (r *ResourceSetInputProviderReconciler) indexBySelectedKind(o client.Object) []string {
+	rsip, ok := o.(*fluxcdv1.ResourceSetInputProvider)
+	if !ok {
+		return nil
+	}
+
+	if rsip.Spec.Type != fluxcdv1.InputProviderKubernetesSelector || rsip.Spec.Selector == nil {
+		return nil
+	}
+
+	gvk := schema.FromAPIVersionAndKind(rsip.Spec.Selector.APIVersion, rsip.Spec.Selector.Kind)
+	return []string{gvk.String()}
+}
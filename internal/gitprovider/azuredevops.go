@@ -0,0 +1,258 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AzureDevOpsProvider implements the Interface for Azure DevOps Repos.
+type AzureDevOpsProvider struct {
+	Client  *http.Client
+	Host    string
+	Project string
+	Repo    string
+	Token   string
+
+	resultLimiter
+}
+
+func NewAzureDevOpsProvider(ctx context.Context, opts Options) (*AzureDevOpsProvider, error) {
+	host, project, repo, err := parseAzureDevOpsURL(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{}
+	if opts.CertPool != nil {
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs: opts.CertPool,
+			},
+		}
+	}
+
+	return &AzureDevOpsProvider{
+		Client:  httpClient,
+		Host:    host,
+		Project: project,
+		Repo:    repo,
+		Token:   opts.Token,
+	}, nil
+}
+
+type azureDevOpsList[T any] struct {
+	Value []T `json:"value"`
+	Count int `json:"count"`
+}
+
+type azureDevOpsRef struct {
+	Name     string `json:"name"`
+	ObjectID string `json:"objectId"`
+}
+
+type azureDevOpsPullRequest struct {
+	PullRequestID int    `json:"pullRequestId"`
+	Title         string `json:"title"`
+	SourceRefName string `json:"sourceRefName"`
+	TargetRefName string `json:"targetRefName"`
+	IsDraft       bool   `json:"isDraft"`
+	ForkSource    *struct {
+		Name string `json:"name"`
+	} `json:"forkSource"`
+	CreationDate time.Time `json:"creationDate"`
+	CreatedBy    struct {
+		UniqueName string `json:"uniqueName"`
+	} `json:"createdBy"`
+	LastMergeSourceCommit struct {
+		CommitID string `json:"commitId"`
+	} `json:"lastMergeSourceCommit"`
+	Labels []struct {
+		Name   string `json:"name"`
+		Active bool   `json:"active"`
+	} `json:"labels"`
+}
+
+// RepositoryPath returns the repository path in the 'project/repo' format.
+func (p *AzureDevOpsProvider) RepositoryPath() string {
+	return p.Project + "/" + p.Repo
+}
+
+func (p *AzureDevOpsProvider) ListBranches(ctx context.Context, opts Options) ([]Result, error) {
+	query := url.Values{
+		"filter": []string{"heads/"},
+		"$top":   []string{"100"},
+	}
+
+	var results []Result
+	for {
+		var list azureDevOpsList[azureDevOpsRef]
+		continuationToken, err := p.get(ctx, "refs", query, &list)
+		if err != nil {
+			return nil, fmt.Errorf("could not list branches: %v", err)
+		}
+
+		for _, ref := range list.Value {
+			branch := strings.TrimPrefix(ref.Name, "refs/heads/")
+			if !matchBranch(opts, branch) {
+				continue
+			}
+
+			results = append(results, Result{
+				ID:     checksum(branch),
+				SHA:    ref.ObjectID,
+				Branch: branch,
+			})
+		}
+
+		if continuationToken == "" {
+			break
+		}
+		query.Set("continuationToken", continuationToken)
+	}
+
+	return p.limit(opts, results), nil
+}
+
+// ListRequests returns the active pull requests that match the filters.
+// The labels of a result are the active tags of the pull request.
+func (p *AzureDevOpsProvider) ListRequests(ctx context.Context, opts Options) ([]Result, error) {
+	const pageSize = 100
+	query := url.Values{
+		"searchCriteria.status": []string{"active"},
+		"$top":                  []string{strconv.Itoa(pageSize)},
+	}
+
+	var results []Result
+	for skip := 0; ; skip += pageSize {
+		query.Set("$skip", strconv.Itoa(skip))
+
+		var list azureDevOpsList[azureDevOpsPullRequest]
+		if _, err := p.get(ctx, "pullrequests", query, &list); err != nil {
+			return nil, fmt.Errorf("could not list pull requests: %v", err)
+		}
+
+		for _, pr := range list.Value {
+			branch := strings.TrimPrefix(pr.SourceRefName, "refs/heads/")
+			if !matchBranch(opts, branch) {
+				continue
+			}
+
+			prLabels := make([]string, 0, len(pr.Labels))
+			for _, l := range pr.Labels {
+				if l.Active {
+					prLabels = append(prLabels, l.Name)
+				}
+			}
+			if !matchLabels(opts, prLabels) {
+				continue
+			}
+
+			if !matchRequest(opts, requestInfo{
+				BaseBranch: strings.TrimPrefix(pr.TargetRefName, "refs/heads/"),
+				Author:     pr.CreatedBy.UniqueName,
+				Draft:      pr.IsDraft,
+				Fork:       pr.ForkSource != nil,
+				CreatedAt:  pr.CreationDate,
+			}) {
+				continue
+			}
+
+			results = append(results, Result{
+				ID:        strconv.Itoa(pr.PullRequestID),
+				SHA:       pr.LastMergeSourceCommit.CommitID,
+				Branch:    branch,
+				Title:     pr.Title,
+				Author:    pr.CreatedBy.UniqueName,
+				Labels:    prLabels,
+				CreatedAt: formatTime(pr.CreationDate),
+			})
+		}
+
+		if len(list.Value) < pageSize {
+			break
+		}
+	}
+
+	return p.limit(opts, results), nil
+}
+
+// ListTags is not supported for Azure DevOps.
+func (p *AzureDevOpsProvider) ListTags(ctx context.Context, opts Options) ([]Result, error) {
+	return nil, fmt.Errorf("listing tags is not supported for Azure DevOps")
+}
+
+// get calls the Git repository API endpoint with the given query and decodes the
+// JSON response into the result. It returns the continuation token of the response.
+func (p *AzureDevOpsProvider) get(ctx context.Context, endpoint string, query url.Values, result any) (string, error) {
+	query.Set("api-version", "7.0")
+	reqURL := fmt.Sprintf("%s/%s/_apis/git/repositories/%s/%s?%s",
+		p.Host, url.PathEscape(p.Project), url.PathEscape(p.Repo), endpoint, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	// Azure DevOps personal access tokens are sent
+	// as basic auth passwords with an empty username.
+	if p.Token != "" {
+		req.SetBasicAuth("", p.Token)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// Azure DevOps redirects unauthenticated requests to the sign-in page
+	// with a 203 Non-Authoritative Information HTML response.
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("GET %s: %s %s", reqURL, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return "", err
+	}
+
+	return resp.Header.Get("x-ms-continuationtoken"), nil
+}
+
+// parseAzureDevOpsURL parses an Azure DevOps Repos URL in the format
+// 'https://dev.azure.com/<org>/<project>/_git/<repo>' and returns the
+// organization URL, the project and the repository name.
+// The legacy 'https://<org>.visualstudio.com/<project>/_git/<repo>' and
+// Azure DevOps Server 'https://<host>/<collection>/<project>/_git/<repo>'
+// formats are also supported.
+func parseAzureDevOpsURL(adoURL string) (string, string, string, error) {
+	u, err := url.Parse(adoURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid URL %q: %w", adoURL, err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := range parts {
+		if parts[i] == "_git" && i > 0 && len(parts) == i+2 && parts[i+1] != "" {
+			host := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+			if i > 1 {
+				host = fmt.Sprintf("%s/%s", host, strings.Join(parts[:i-1], "/"))
+			}
+			return host, parts[i-1], parts[i+1], nil
+		}
+	}
+
+	return "", "", "", fmt.Errorf("invalid Azure DevOps URL %q: can't find project and repository", adoURL)
+}
@@ -0,0 +1,339 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func newAzureDevOpsTestServer(t *testing.T) *httptest.Server {
+	refsHandler := func(w http.ResponseWriter, r *http.Request) {
+		if _, pass, _ := r.BasicAuth(); pass != "test-pat" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("continuationToken") {
+		case "":
+			w.Header().Set("x-ms-continuationtoken", "page2")
+			_, _ = w.Write([]byte(`{"count":2,"value":[
+{"name":"refs/heads/main","objectId":"a1b2c3d4e5f60718293a4b5c6d7e8f9001122334"},
+{"name":"refs/heads/patch-1","objectId":"2dd3a8d2088457e5cf991018edf13e25cbd61380"}
+]}`))
+		default:
+			_, _ = w.Write([]byte(`{"count":1,"value":[
+{"name":"refs/heads/patch-2","objectId":"1e5aef14d38a8c67e5240308adf2935d6cdc2ec8"}
+]}`))
+		}
+	}
+	prsHandler := func(w http.ResponseWriter, r *http.Request) {
+		if _, pass, _ := r.BasicAuth(); pass != "test-pat" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("searchCriteria.status") != "active" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count":2,"value":[
+{"pullRequestId":12,"title":"test2: Update README.md","sourceRefName":"refs/heads/patch-2",
+ "targetRefName":"refs/heads/main",
+ "creationDate":"2025-01-02T10:00:00.1234567Z",
+ "createdBy":{"uniqueName":"stefan@example.com"},
+ "lastMergeSourceCommit":{"commitId":"1e5aef14d38a8c67e5240308adf2935d6cdc2ec8"},
+ "labels":[{"name":"preview","active":true},{"name":"old","active":false}]},
+{"pullRequestId":11,"title":"test1: Update README.md","sourceRefName":"refs/heads/patch-1",
+ "targetRefName":"refs/heads/main","isDraft":true,"forkSource":{"name":"refs/heads/patch-1"},
+ "creationDate":"2025-01-01T10:00:00.1234567Z",
+ "createdBy":{"uniqueName":"stefan@example.com"},
+ "lastMergeSourceCommit":{"commitId":"2dd3a8d2088457e5cf991018edf13e25cbd61380"}}
+]}`))
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/org/My Project/_apis/git/repositories/app/refs":
+			refsHandler(w, r)
+		case "/org/My Project/_apis/git/repositories/app/pullrequests":
+			prsHandler(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAzureDevOpsProvider_ListBranches(t *testing.T) {
+	srv := newAzureDevOpsTestServer(t)
+
+	tests := []struct {
+		name       string
+		opts       Options
+		want       []Result
+		wantErrMsg string
+	}{
+		{
+			name: "filters branches by regex across pages",
+			opts: Options{
+				Token: "test-pat",
+				URL:   srv.URL + "/org/My%20Project/_git/app",
+				Filters: Filters{
+					IncludeBranchRe: regexp.MustCompile(`^patch-.*`),
+				},
+			},
+			want: []Result{
+				{
+					ID:     "183501423",
+					SHA:    "2dd3a8d2088457e5cf991018edf13e25cbd61380",
+					Branch: "patch-1",
+				},
+				{
+					ID:     "183566960",
+					SHA:    "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
+					Branch: "patch-2",
+				},
+			},
+		},
+		{
+			name: "wrong token",
+			opts: Options{
+				Token: "wrong-pat",
+				URL:   srv.URL + "/org/My%20Project/_git/app",
+			},
+			wantErrMsg: "401 Unauthorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewAzureDevOpsProvider(context.Background(), tt.opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListBranches(context.Background(), tt.opts)
+			if len(tt.wantErrMsg) > 0 {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(BeEquivalentTo(tt.want))
+		})
+	}
+}
+
+func TestAzureDevOpsProvider_ListRequests(t *testing.T) {
+	srv := newAzureDevOpsTestServer(t)
+
+	tests := []struct {
+		name       string
+		opts       Options
+		want       []Result
+		wantErrMsg string
+	}{
+		{
+			name: "all prs",
+			opts: Options{
+				Token: "test-pat",
+				URL:   srv.URL + "/org/My%20Project/_git/app",
+			},
+			want: []Result{
+				{
+					ID:        "12",
+					SHA:       "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
+					Title:     "test2: Update README.md",
+					Author:    "stefan@example.com",
+					Branch:    "patch-2",
+					Labels:    []string{"preview"},
+					CreatedAt: "2025-01-02T10:00:00Z",
+				},
+				{
+					ID:        "11",
+					SHA:       "2dd3a8d2088457e5cf991018edf13e25cbd61380",
+					Title:     "test1: Update README.md",
+					Author:    "stefan@example.com",
+					Branch:    "patch-1",
+					Labels:    []string{},
+					CreatedAt: "2025-01-01T10:00:00Z",
+				},
+			},
+		},
+		{
+			name: "filters prs by tags",
+			opts: Options{
+				Token: "test-pat",
+				URL:   srv.URL + "/org/My%20Project/_git/app",
+				Filters: Filters{
+					Labels: []string{"preview"},
+				},
+			},
+			want: []Result{
+				{
+					ID:        "12",
+					SHA:       "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
+					Title:     "test2: Update README.md",
+					Author:    "stefan@example.com",
+					Branch:    "patch-2",
+					Labels:    []string{"preview"},
+					CreatedAt: "2025-01-02T10:00:00Z",
+				},
+			},
+		},
+		{
+			name: "filters prs by inactive tags",
+			opts: Options{
+				Token: "test-pat",
+				URL:   srv.URL + "/org/My%20Project/_git/app",
+				Filters: Filters{
+					Labels: []string{"old"},
+				},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewAzureDevOpsProvider(context.Background(), tt.opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListRequests(context.Background(), tt.opts)
+			if len(tt.wantErrMsg) > 0 {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(BeEquivalentTo(tt.want))
+		})
+	}
+}
+
+func TestAzureDevOpsProvider_ListRequests_RequestFilters(t *testing.T) {
+	srv := newAzureDevOpsTestServer(t)
+
+	tests := []struct {
+		name    string
+		filters Filters
+		want    []string
+	}{
+		{
+			name: "filters prs by base branch",
+			filters: Filters{
+				BaseBranchRe: regexp.MustCompile(`^main$`),
+			},
+			want: []string{"12", "11"},
+		},
+		{
+			name: "excludes draft and fork prs",
+			filters: Filters{
+				BaseBranchRe: regexp.MustCompile(`^main$`),
+				ExcludeDraft: true,
+				ExcludeForks: true,
+			},
+			want: []string{"12"},
+		},
+		{
+			name: "filters prs by author and max age",
+			filters: Filters{
+				IncludeAuthors: []string{"STEFAN@EXAMPLE.COM"},
+				MaxAge:         time.Since(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)),
+			},
+			want: []string{"12"},
+		},
+		{
+			name: "excludes prs by author",
+			filters: Filters{
+				ExcludeAuthors: []string{"stefan@example.com"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts := Options{
+				Token:   "test-pat",
+				URL:     srv.URL + "/org/My%20Project/_git/app",
+				Filters: tt.filters,
+			}
+			provider, err := NewAzureDevOpsProvider(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListRequests(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var ids []string
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+			g.Expect(ids).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestParseAzureDevOpsURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		host    string
+		project string
+		repo    string
+		wantErr bool
+	}{
+		{
+			url:     "https://dev.azure.com/org/project/_git/repo",
+			host:    "https://dev.azure.com/org",
+			project: "project",
+			repo:    "repo",
+		},
+		{
+			url:     "https://org.visualstudio.com/project/_git/repo",
+			host:    "https://org.visualstudio.com",
+			project: "project",
+			repo:    "repo",
+		},
+		{
+			url:     "https://tfs.example.com/tfs/DefaultCollection/project/_git/repo",
+			host:    "https://tfs.example.com/tfs/DefaultCollection",
+			project: "project",
+			repo:    "repo",
+		},
+		{
+			url:     "https://dev.azure.com/org/project",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			g := NewWithT(t)
+
+			host, project, repo, err := parseAzureDevOpsURL(tt.url)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(host).To(Equal(tt.host))
+			g.Expect(project).To(Equal(tt.project))
+			g.Expect(repo).To(Equal(tt.repo))
+		})
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BitbucketServerProvider implements the Interface for
+// Bitbucket Server and Bitbucket Data Center.
+type BitbucketServerProvider struct {
+	Client   *http.Client
+	Host     string
+	Project  string
+	Repo     string
+	Username string
+	Token    string
+
+	resultLimiter
+}
+
+func NewBitbucketServerProvider(ctx context.Context, opts Options) (*BitbucketServerProvider, error) {
+	host, project, repo, err := parseBitbucketServerURL(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{}
+	if opts.CertPool != nil {
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs: opts.CertPool,
+			},
+		}
+	}
+
+	return &BitbucketServerProvider{
+		Client:   httpClient,
+		Host:     host,
+		Project:  project,
+		Repo:     repo,
+		Username: opts.Username,
+		Token:    opts.Token,
+	}, nil
+}
+
+// bitbucketPage is the paged response envelope of the Bitbucket Server REST API.
+type bitbucketPage[T any] struct {
+	Values        []T  `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+type bitbucketRef struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+	Repository   struct {
+		ID int `json:"id"`
+	} `json:"repository"`
+}
+
+type bitbucketUser struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type bitbucketParticipant struct {
+	User bitbucketUser `json:"user"`
+}
+
+type bitbucketPullRequest struct {
+	ID          int                    `json:"id"`
+	Title       string                 `json:"title"`
+	Author      bitbucketParticipant   `json:"author"`
+	FromRef     bitbucketRef           `json:"fromRef"`
+	ToRef       bitbucketRef           `json:"toRef"`
+	Draft       bool                   `json:"draft"`
+	Reviewers   []bitbucketParticipant `json:"reviewers"`
+	CreatedDate int64                  `json:"createdDate"`
+	UpdatedDate int64                  `json:"updatedDate"`
+}
+
+// RepositoryPath returns the repository path in the 'project/repo' format.
+func (p *BitbucketServerProvider) RepositoryPath() string {
+	return p.Project + "/" + p.Repo
+}
+
+func (p *BitbucketServerProvider) ListBranches(ctx context.Context, opts Options) ([]Result, error) {
+	var results []Result
+	start := 0
+	for {
+		var page bitbucketPage[bitbucketRef]
+		if err := p.get(ctx, "branches", url.Values{}, start, &page); err != nil {
+			return nil, fmt.Errorf("could not list branches: %v", err)
+		}
+
+		for _, branch := range page.Values {
+			if !matchBranch(opts, branch.DisplayID) {
+				continue
+			}
+
+			results = append(results, Result{
+				ID:     checksum(branch.DisplayID),
+				SHA:    branch.LatestCommit,
+				Branch: branch.DisplayID,
+			})
+		}
+
+		if page.IsLastPage {
+			break
+		}
+		start = page.NextPageStart
+	}
+
+	return p.limit(opts, results), nil
+}
+
+// ListRequests returns the open pull requests that match the filters.
+// Bitbucket Server has no pull request labels, the labels of a result are
+// made of the reviewers usernames and the tags found in the pull request title,
+// see bitbucketLabels.
+func (p *BitbucketServerProvider) ListRequests(ctx context.Context, opts Options) ([]Result, error) {
+	var results []Result
+	start := 0
+	for {
+		var page bitbucketPage[bitbucketPullRequest]
+		query := url.Values{
+			"state": []string{"OPEN"},
+			"order": []string{"NEWEST"},
+		}
+		if err := p.get(ctx, "pull-requests", query, start, &page); err != nil {
+			return nil, fmt.Errorf("could not list pull requests: %v", err)
+		}
+
+		for _, pr := range page.Values {
+			if !matchBranch(opts, pr.FromRef.DisplayID) {
+				continue
+			}
+
+			prLabels := bitbucketLabels(pr, opts.Filters.TitleLabelPrefix)
+			if !matchLabels(opts, prLabels) {
+				continue
+			}
+
+			if !matchRequest(opts, requestInfo{
+				BaseBranch: pr.ToRef.DisplayID,
+				Author:     pr.Author.User.Name,
+				Draft:      pr.Draft,
+				Fork:       pr.FromRef.Repository.ID != pr.ToRef.Repository.ID,
+				CreatedAt:  epochMillis(pr.CreatedDate),
+			}) {
+				continue
+			}
+
+			results = append(results, Result{
+				ID:        strconv.Itoa(pr.ID),
+				SHA:       pr.FromRef.LatestCommit,
+				Branch:    pr.FromRef.DisplayID,
+				Title:     pr.Title,
+				Author:    pr.Author.User.Name,
+				Labels:    prLabels,
+				CreatedAt: formatTime(epochMillis(pr.CreatedDate)),
+				UpdatedAt: formatTime(epochMillis(pr.UpdatedDate)),
+			})
+		}
+
+		if page.IsLastPage {
+			break
+		}
+		start = page.NextPageStart
+	}
+
+	return p.limit(opts, results), nil
+}
+
+// ListTags is not supported for Bitbucket Server.
+func (p *BitbucketServerProvider) ListTags(ctx context.Context, opts Options) ([]Result, error) {
+	return nil, fmt.Errorf("listing tags is not supported for Bitbucket Server")
+}
+
+// get calls the repository API endpoint with the given query
+// and decodes the JSON response into the result.
+func (p *BitbucketServerProvider) get(ctx context.Context, endpoint string, query url.Values, start int, result any) error {
+	query.Set("start", strconv.Itoa(start))
+	query.Set("limit", "100")
+	reqURL := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/%s?%s",
+		p.Host, url.PathEscape(p.Project), url.PathEscape(p.Repo), endpoint, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	// Use basic auth when a username is provided, otherwise
+	// use the token as a Bitbucket HTTP access token.
+	if p.Token != "" {
+		if p.Username != "" {
+			req.SetBasicAuth(p.Username, p.Token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+p.Token)
+		}
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("GET %s: %s %s", reqURL, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+var bitbucketTitleTagRe = regexp.MustCompile(`^\s*\[([^\]]+)\]`)
+
+// bitbucketLabels returns the reviewers usernames and the tags found in the
+// PR title. When the prefix is set, the title words starting with the prefix
+// are used as tags (e.g. with '#' the title 'Fix login #preview' has the tag
+// 'preview'), otherwise the bracketed tags found at the start of the title
+// are used (e.g. '[preview] Fix login' has the tag 'preview').
+func bitbucketLabels(pr bitbucketPullRequest, prefix string) []string {
+	labels := make([]string, 0)
+
+	if prefix != "" {
+		for _, word := range strings.Fields(pr.Title) {
+			if tag, ok := strings.CutPrefix(word, prefix); ok && tag != "" {
+				labels = append(labels, tag)
+			}
+		}
+	} else {
+		title := pr.Title
+		for {
+			m := bitbucketTitleTagRe.FindStringSubmatchIndex(title)
+			if m == nil {
+				break
+			}
+			labels = append(labels, strings.TrimSpace(title[m[2]:m[3]]))
+			title = title[m[1]:]
+		}
+	}
+
+	for _, r := range pr.Reviewers {
+		labels = append(labels, r.User.Name)
+	}
+
+	return labels
+}
+
+// parseBitbucketServerURL parses a Bitbucket Server URL and returns the host,
+// project key and repository slug. Both the browse URL format
+// 'https://host/projects/<project>/repos/<repo>' and the clone URL format
+// 'https://host/scm/<project>/<repo>.git' are supported.
+func parseBitbucketServerURL(bbURL string) (string, string, string, error) {
+	u, err := url.Parse(bbURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid URL %q: %w", bbURL, err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := range parts {
+		switch {
+		case parts[i] == "projects" && len(parts) >= i+4 && parts[i+2] == "repos":
+			host := bitbucketHost(u, parts[:i])
+			return host, parts[i+1], parts[i+3], nil
+		case parts[i] == "scm" && len(parts) == i+3:
+			host := bitbucketHost(u, parts[:i])
+			return host, parts[i+1], strings.TrimSuffix(parts[i+2], ".git"), nil
+		}
+	}
+
+	return "", "", "", fmt.Errorf("invalid Bitbucket Server URL %q: can't find project and repository", bbURL)
+}
+
+// bitbucketHost returns the base URL of the server including the context path.
+func bitbucketHost(u *url.URL, contextPath []string) string {
+	host := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+	if len(contextPath) > 0 {
+		host = fmt.Sprintf("%s/%s", host, strings.Join(contextPath, "/"))
+	}
+	return host
+}
+
+// epochMillis returns the time from the Unix epoch milliseconds
+// used by the Bitbucket Server API, or the zero time if not set.
+func epochMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
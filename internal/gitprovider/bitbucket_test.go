@@ -0,0 +1,384 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func newBitbucketServerTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bitbucket/rest/api/1.0/projects/PRJ/repos/app/branches", func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "flux" || pass != "test-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Authentication failed"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("start") {
+		case "0":
+			_, _ = w.Write([]byte(`{"isLastPage":false,"nextPageStart":2,"values":[
+{"id":"refs/heads/main","displayId":"main","latestCommit":"a1b2c3d4e5f60718293a4b5c6d7e8f9001122334"},
+{"id":"refs/heads/patch-1","displayId":"patch-1","latestCommit":"2dd3a8d2088457e5cf991018edf13e25cbd61380"}
+]}`))
+		default:
+			_, _ = w.Write([]byte(`{"isLastPage":true,"values":[
+{"id":"refs/heads/patch-2","displayId":"patch-2","latestCommit":"1e5aef14d38a8c67e5240308adf2935d6cdc2ec8"}
+]}`))
+		}
+	})
+	mux.HandleFunc("/bitbucket/rest/api/1.0/projects/PRJ/repos/app/pull-requests", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Authentication failed"}]}`))
+			return
+		}
+		if r.URL.Query().Get("state") != "OPEN" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"isLastPage":true,"values":[
+{"id":3,"title":"[preview] test3: Update README.md","author":{"user":{"name":"stefanprodan"}},
+ "createdDate":1735898400000,"updatedDate":1735905600000,
+ "fromRef":{"displayId":"patch-3","latestCommit":"29d1d3a726e1e1f68b7cb60ac891cb83fa260ea9","repository":{"id":1}},
+ "toRef":{"displayId":"main","repository":{"id":1}},
+ "reviewers":[{"user":{"name":"alice"}}]},
+{"id":2,"title":"test2: Update README.md #preview","author":{"user":{"name":"stefanprodan"}},
+ "createdDate":1735812000000,"updatedDate":1735819200000,
+ "fromRef":{"displayId":"patch-2","latestCommit":"1e5aef14d38a8c67e5240308adf2935d6cdc2ec8","repository":{"id":1}},
+ "toRef":{"displayId":"release","repository":{"id":1}},
+ "reviewers":[{"user":{"name":"alice"}},{"user":{"name":"bob"}}]},
+{"id":1,"title":"[preview][wip] test1: Update README.md","author":{"user":{"name":"stefanprodan"}},
+ "createdDate":1735725600000,"updatedDate":1735732800000,
+ "fromRef":{"displayId":"feat/1","latestCommit":"2dd3a8d2088457e5cf991018edf13e25cbd61380","repository":{"id":2}},
+ "toRef":{"displayId":"main","repository":{"id":1}},"draft":true,
+ "reviewers":[]}
+]}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBitbucketServerProvider_ListBranches(t *testing.T) {
+	srv := newBitbucketServerTestServer(t)
+
+	tests := []struct {
+		name       string
+		opts       Options
+		want       []Result
+		wantErrMsg string
+	}{
+		{
+			name: "filters branches by regex across pages",
+			opts: Options{
+				Username: "flux",
+				Token:    "test-password",
+				URL:      srv.URL + "/bitbucket/projects/PRJ/repos/app",
+				Filters: Filters{
+					IncludeBranchRe: regexp.MustCompile(`^patch-.*`),
+				},
+			},
+			want: []Result{
+				{
+					ID:     "183501423",
+					SHA:    "2dd3a8d2088457e5cf991018edf13e25cbd61380",
+					Branch: "patch-1",
+				},
+				{
+					ID:     "183566960",
+					SHA:    "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
+					Branch: "patch-2",
+				},
+			},
+		},
+		{
+			name: "filters branches by limit",
+			opts: Options{
+				Username: "flux",
+				Token:    "test-password",
+				URL:      srv.URL + "/bitbucket/scm/PRJ/app.git",
+				Filters: Filters{
+					Limit: 1,
+				},
+			},
+			want: []Result{
+				{
+					ID:     "68878758",
+					SHA:    "a1b2c3d4e5f60718293a4b5c6d7e8f9001122334",
+					Branch: "main",
+				},
+			},
+		},
+		{
+			name: "wrong password",
+			opts: Options{
+				Username: "flux",
+				Token:    "wrong-password",
+				URL:      srv.URL + "/bitbucket/projects/PRJ/repos/app",
+			},
+			wantErrMsg: "401 Unauthorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewBitbucketServerProvider(context.Background(), tt.opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListBranches(context.Background(), tt.opts)
+			if len(tt.wantErrMsg) > 0 {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(BeEquivalentTo(tt.want))
+		})
+	}
+}
+
+func TestBitbucketServerProvider_ListRequests(t *testing.T) {
+	srv := newBitbucketServerTestServer(t)
+
+	tests := []struct {
+		name       string
+		opts       Options
+		want       []Result
+		wantErrMsg string
+	}{
+		{
+			name: "filters prs by title prefix",
+			opts: Options{
+				Token: "test-token",
+				URL:   srv.URL + "/bitbucket/projects/PRJ/repos/app",
+				Filters: Filters{
+					Labels: []string{"preview"},
+				},
+			},
+			want: []Result{
+				{
+					ID:        "3",
+					SHA:       "29d1d3a726e1e1f68b7cb60ac891cb83fa260ea9",
+					Title:     "[preview] test3: Update README.md",
+					Author:    "stefanprodan",
+					Branch:    "patch-3",
+					Labels:    []string{"preview", "alice"},
+					CreatedAt: "2025-01-03T10:00:00Z",
+					UpdatedAt: "2025-01-03T12:00:00Z",
+				},
+				{
+					ID:        "1",
+					SHA:       "2dd3a8d2088457e5cf991018edf13e25cbd61380",
+					Title:     "[preview][wip] test1: Update README.md",
+					Author:    "stefanprodan",
+					Branch:    "feat/1",
+					Labels:    []string{"preview", "wip"},
+					CreatedAt: "2025-01-01T10:00:00Z",
+					UpdatedAt: "2025-01-01T12:00:00Z",
+				},
+			},
+		},
+		{
+			name: "filters prs by reviewers and branches",
+			opts: Options{
+				Token: "test-token",
+				URL:   srv.URL + "/bitbucket/projects/PRJ/repos/app",
+				Filters: Filters{
+					IncludeBranchRe: regexp.MustCompile(`^patch-.*`),
+					Labels:          []string{"bob"},
+				},
+			},
+			want: []Result{
+				{
+					ID:        "2",
+					SHA:       "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
+					Title:     "test2: Update README.md #preview",
+					Author:    "stefanprodan",
+					Branch:    "patch-2",
+					Labels:    []string{"alice", "bob"},
+					CreatedAt: "2025-01-02T10:00:00Z",
+					UpdatedAt: "2025-01-02T12:00:00Z",
+				},
+			},
+		},
+		{
+			name: "filters prs by title label prefix",
+			opts: Options{
+				Token: "test-token",
+				URL:   srv.URL + "/bitbucket/projects/PRJ/repos/app",
+				Filters: Filters{
+					Labels:           []string{"preview"},
+					TitleLabelPrefix: "#",
+				},
+			},
+			want: []Result{
+				{
+					ID:        "2",
+					SHA:       "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
+					Title:     "test2: Update README.md #preview",
+					Author:    "stefanprodan",
+					Branch:    "patch-2",
+					Labels:    []string{"preview", "alice", "bob"},
+					CreatedAt: "2025-01-02T10:00:00Z",
+					UpdatedAt: "2025-01-02T12:00:00Z",
+				},
+			},
+		},
+		{
+			name: "wrong token",
+			opts: Options{
+				Token: "wrong-token",
+				URL:   srv.URL + "/bitbucket/projects/PRJ/repos/app",
+			},
+			wantErrMsg: "Authentication failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewBitbucketServerProvider(context.Background(), tt.opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListRequests(context.Background(), tt.opts)
+			if len(tt.wantErrMsg) > 0 {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(BeEquivalentTo(tt.want))
+		})
+	}
+}
+
+func TestBitbucketServerProvider_ListRequests_RequestFilters(t *testing.T) {
+	srv := newBitbucketServerTestServer(t)
+
+	tests := []struct {
+		name    string
+		filters Filters
+		want    []string
+	}{
+		{
+			name: "filters prs by base branch",
+			filters: Filters{
+				BaseBranchRe: regexp.MustCompile(`^main$`),
+			},
+			want: []string{"3", "1"},
+		},
+		{
+			name: "excludes draft and fork prs",
+			filters: Filters{
+				BaseBranchRe: regexp.MustCompile(`^main$`),
+				ExcludeDraft: true,
+				ExcludeForks: true,
+			},
+			want: []string{"3"},
+		},
+		{
+			name: "filters prs by author and max age",
+			filters: Filters{
+				IncludeAuthors: []string{"STEFANPRODAN"},
+				MaxAge:         time.Since(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)),
+			},
+			want: []string{"3", "2"},
+		},
+		{
+			name: "excludes prs by author",
+			filters: Filters{
+				ExcludeAuthors: []string{"stefanprodan"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts := Options{
+				Token:   "test-token",
+				URL:     srv.URL + "/bitbucket/projects/PRJ/repos/app",
+				Filters: tt.filters,
+			}
+			provider, err := NewBitbucketServerProvider(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListRequests(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var ids []string
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+			g.Expect(ids).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestParseBitbucketServerURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		host    string
+		project string
+		repo    string
+		wantErr bool
+	}{
+		{
+			url:     "https://bitbucket.example.com/projects/PRJ/repos/app",
+			host:    "https://bitbucket.example.com",
+			project: "PRJ",
+			repo:    "app",
+		},
+		{
+			url:     "https://bitbucket.example.com/projects/PRJ/repos/app/browse",
+			host:    "https://bitbucket.example.com",
+			project: "PRJ",
+			repo:    "app",
+		},
+		{
+			url:     "https://example.com/bitbucket/scm/prj/app.git",
+			host:    "https://example.com/bitbucket",
+			project: "prj",
+			repo:    "app",
+		},
+		{
+			url:     "https://bitbucket.example.com/PRJ/app",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			g := NewWithT(t)
+
+			host, project, repo, err := parseBitbucketServerURL(tt.url)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(host).To(Equal(tt.host))
+			g.Expect(project).To(Equal(tt.project))
+			g.Expect(repo).To(Equal(tt.repo))
+		})
+	}
+}
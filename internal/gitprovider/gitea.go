@@ -0,0 +1,227 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+type GiteaProvider struct {
+	Client *gitea.Client
+	Owner  string
+	Repo   string
+
+	resultLimiter
+}
+
+func NewGiteaProvider(ctx context.Context, opts Options) (*GiteaProvider, error) {
+	host, owner, repo, err := parseGiteaURL(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{}
+	if opts.CertPool != nil {
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs: opts.CertPool,
+			},
+		}
+	}
+
+	// Skip the server version check as it requires an extra API call
+	// and the endpoints used by the provider are available in all
+	// supported Gitea and Forgejo releases.
+	giteaOpts := []gitea.ClientOption{
+		gitea.SetContext(ctx),
+		gitea.SetHTTPClient(httpClient),
+		gitea.SetGiteaVersion(""),
+	}
+	if opts.Token != "" {
+		giteaOpts = append(giteaOpts, gitea.SetToken(opts.Token))
+	}
+
+	client, err := gitea.NewClient(host, giteaOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Gitea client: %v", err)
+	}
+
+	return &GiteaProvider{
+		Client: client,
+		Owner:  owner,
+		Repo:   repo,
+	}, nil
+}
+
+// RepositoryPath returns the repository path in the 'owner/repo' format.
+func (p *GiteaProvider) RepositoryPath() string {
+	return p.Owner + "/" + p.Repo
+}
+
+func (p *GiteaProvider) ListBranches(ctx context.Context, opts Options) ([]Result, error) {
+	p.Client.SetContext(ctx)
+	gtOpts := gitea.ListRepoBranchesOptions{
+		ListOptions: gitea.ListOptions{
+			Page:     1,
+			PageSize: 50,
+		},
+	}
+
+	var results []Result
+	for {
+		branches, resp, err := p.Client.ListRepoBranches(p.Owner, p.Repo, gtOpts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list branches: %v", err)
+		}
+
+		for _, branch := range branches {
+			if !matchBranch(opts, branch.Name) {
+				continue
+			}
+
+			var sha string
+			if branch.Commit != nil {
+				sha = branch.Commit.ID
+			}
+
+			results = append(results, Result{
+				ID:     checksum(branch.Name),
+				SHA:    sha,
+				Branch: branch.Name,
+			})
+		}
+
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		gtOpts.Page = resp.NextPage
+	}
+
+	return p.limit(opts, results), nil
+}
+
+func (p *GiteaProvider) ListRequests(ctx context.Context, opts Options) ([]Result, error) {
+	p.Client.SetContext(ctx)
+	gtOpts := gitea.ListPullRequestsOptions{
+		State: gitea.StateOpen,
+		ListOptions: gitea.ListOptions{
+			Page:     1,
+			PageSize: 50,
+		},
+	}
+
+	var results []Result
+	for {
+		prs, resp, err := p.Client.ListRepoPullRequests(p.Owner, p.Repo, gtOpts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list pull requests: %v", err)
+		}
+
+		for _, pr := range prs {
+			if pr.Head == nil || !matchBranch(opts, pr.Head.Ref) {
+				continue
+			}
+
+			prLabels := make([]string, len(pr.Labels))
+			for i, l := range pr.Labels {
+				prLabels[i] = l.Name
+			}
+			if !matchLabels(opts, prLabels) {
+				continue
+			}
+
+			var author string
+			if pr.Poster != nil {
+				author = pr.Poster.UserName
+			}
+
+			var baseBranch string
+			var baseRepoID int64
+			if pr.Base != nil {
+				baseBranch = pr.Base.Ref
+				baseRepoID = pr.Base.RepoID
+			}
+
+			var created time.Time
+			if pr.Created != nil {
+				created = *pr.Created
+			}
+
+			if !matchRequest(opts, requestInfo{
+				BaseBranch: baseBranch,
+				Author:     author,
+				Draft:      giteaDraft(pr.Title),
+				Fork:       pr.Head.RepoID != baseRepoID,
+				CreatedAt:  created,
+			}) {
+				continue
+			}
+
+			var updatedAt string
+			if pr.Updated != nil {
+				updatedAt = formatTime(*pr.Updated)
+			}
+
+			results = append(results, Result{
+				ID:        fmt.Sprintf("%d", pr.Index),
+				SHA:       pr.Head.Sha,
+				Branch:    pr.Head.Ref,
+				Title:     pr.Title,
+				Author:    author,
+				Labels:    prLabels,
+				CreatedAt: formatTime(created),
+				UpdatedAt: updatedAt,
+			})
+		}
+
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		gtOpts.Page = resp.NextPage
+	}
+
+	return p.limit(opts, results), nil
+}
+
+// ListTags is not supported for Gitea.
+func (p *GiteaProvider) ListTags(ctx context.Context, opts Options) ([]Result, error) {
+	return nil, fmt.Errorf("listing tags is not supported for Gitea")
+}
+
+// parseGiteaURL parses a Gitea URL and returns the host, owner, and repo.
+// The host includes the path prefix for instances served from a sub-path.
+func parseGiteaURL(gtURL string) (string, string, string, error) {
+	u, err := url.Parse(gtURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid URL %q: %w", gtURL, err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", "", "", fmt.Errorf("invalid Gitea URL %q: can't find owner and repository", gtURL)
+	}
+
+	prefix := strings.Join(parts[:len(parts)-2], "/")
+	host := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+	if prefix != "" {
+		host = fmt.Sprintf("%s/%s", host, prefix)
+	}
+
+	return host, parts[len(parts)-2], strings.TrimSuffix(parts[len(parts)-1], ".git"), nil
+}
+
+// giteaDraft returns true if the pull request title starts with
+// one of the default Gitea work in progress prefixes.
+func giteaDraft(title string) bool {
+	title = strings.ToUpper(title)
+	return strings.HasPrefix(title, "WIP:") || strings.HasPrefix(title, "[WIP]")
+}
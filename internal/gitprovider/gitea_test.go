@@ -0,0 +1,322 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func newGiteaTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/org/app/branches", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"token is required"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/repos/org/app/branches?page=2&limit=50>; rel="next"`, r.Host))
+			_, _ = w.Write([]byte(`[
+{"name":"main","commit":{"id":"a1b2c3d4e5f60718293a4b5c6d7e8f9001122334"}},
+{"name":"patch-1","commit":{"id":"2dd3a8d2088457e5cf991018edf13e25cbd61380"}}
+]`))
+		default:
+			_, _ = w.Write([]byte(`[
+{"name":"patch-2","commit":{"id":"1e5aef14d38a8c67e5240308adf2935d6cdc2ec8"}},
+{"name":"patch-3","commit":{"id":"29d1d3a726e1e1f68b7cb60ac891cb83fa260ea9"}}
+]`))
+		}
+	})
+	mux.HandleFunc("/api/v1/repos/org/app/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "open" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+{"number":3,"title":"test3: Update README.md","user":{"login":"stefanprodan"},
+ "base":{"ref":"main","repo_id":1},
+ "created_at":"2025-01-03T10:00:00Z","updated_at":"2025-01-03T12:00:00Z",
+ "labels":[{"name":"documentation"}],
+ "head":{"ref":"patch-3","sha":"29d1d3a726e1e1f68b7cb60ac891cb83fa260ea9","repo_id":1}},
+{"number":2,"title":"test2: Update README.md","user":{"login":"stefanprodan"},
+ "base":{"ref":"release","repo_id":1},
+ "created_at":"2025-01-02T10:00:00Z","updated_at":"2025-01-02T12:00:00Z",
+ "labels":[{"name":"enhancement"},{"name":"documentation"}],
+ "head":{"ref":"patch-2","sha":"1e5aef14d38a8c67e5240308adf2935d6cdc2ec8","repo_id":1}},
+{"number":1,"title":"WIP: test1: Update README.md","user":{"login":"stefanprodan"},
+ "base":{"ref":"main","repo_id":1},
+ "created_at":"2025-01-01T10:00:00Z","updated_at":"2025-01-01T12:00:00Z",
+ "labels":[],
+ "head":{"ref":"feat/1","sha":"2dd3a8d2088457e5cf991018edf13e25cbd61380","repo_id":2}}
+]`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGiteaProvider_ListBranches(t *testing.T) {
+	srv := newGiteaTestServer(t)
+
+	tests := []struct {
+		name       string
+		opts       Options
+		want       []Result
+		wantErrMsg string
+	}{
+		{
+			name: "filters branches by regex across pages",
+			opts: Options{
+				Token: "test-token",
+				URL:   srv.URL + "/org/app",
+				Filters: Filters{
+					IncludeBranchRe: regexp.MustCompile(`^patch-.*`),
+					ExcludeBranchRe: regexp.MustCompile(`^patch-3`),
+				},
+			},
+			want: []Result{
+				{
+					ID:     "183501423",
+					SHA:    "2dd3a8d2088457e5cf991018edf13e25cbd61380",
+					Branch: "patch-1",
+				},
+				{
+					ID:     "183566960",
+					SHA:    "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
+					Branch: "patch-2",
+				},
+			},
+		},
+		{
+			name: "filters branches by limit",
+			opts: Options{
+				Token: "test-token",
+				URL:   srv.URL + "/org/app",
+				Filters: Filters{
+					IncludeBranchRe: regexp.MustCompile(`^patch-.*`),
+					Limit:           1,
+				},
+			},
+			want: []Result{
+				{
+					ID:     "183501423",
+					SHA:    "2dd3a8d2088457e5cf991018edf13e25cbd61380",
+					Branch: "patch-1",
+				},
+			},
+		},
+		{
+			name: "wrong token",
+			opts: Options{
+				Token: "wrong-token",
+				URL:   srv.URL + "/org/app",
+			},
+			wantErrMsg: "token is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewGiteaProvider(context.Background(), tt.opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListBranches(context.Background(), tt.opts)
+			if len(tt.wantErrMsg) > 0 {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(BeEquivalentTo(tt.want))
+		})
+	}
+}
+
+func TestGiteaProvider_ListRequests(t *testing.T) {
+	srv := newGiteaTestServer(t)
+
+	tests := []struct {
+		name       string
+		opts       Options
+		want       []Result
+		wantErrMsg string
+	}{
+		{
+			name: "filters prs by labels and branches",
+			opts: Options{
+				URL: srv.URL + "/org/app",
+				Filters: Filters{
+					IncludeBranchRe: regexp.MustCompile(`^patch-.*`),
+					Labels:          []string{"documentation"},
+				},
+			},
+			want: []Result{
+				{
+					ID:        "3",
+					SHA:       "29d1d3a726e1e1f68b7cb60ac891cb83fa260ea9",
+					Title:     "test3: Update README.md",
+					Author:    "stefanprodan",
+					Branch:    "patch-3",
+					Labels:    []string{"documentation"},
+					CreatedAt: "2025-01-03T10:00:00Z",
+					UpdatedAt: "2025-01-03T12:00:00Z",
+				},
+				{
+					ID:        "2",
+					SHA:       "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
+					Title:     "test2: Update README.md",
+					Author:    "stefanprodan",
+					Branch:    "patch-2",
+					Labels:    []string{"enhancement", "documentation"},
+					CreatedAt: "2025-01-02T10:00:00Z",
+					UpdatedAt: "2025-01-02T12:00:00Z",
+				},
+			},
+		},
+		{
+			name: "filters prs by limit",
+			opts: Options{
+				URL: srv.URL + "/org/app",
+				Filters: Filters{
+					Limit: 1,
+				},
+			},
+			want: []Result{
+				{
+					ID:        "3",
+					SHA:       "29d1d3a726e1e1f68b7cb60ac891cb83fa260ea9",
+					Title:     "test3: Update README.md",
+					Author:    "stefanprodan",
+					Branch:    "patch-3",
+					Labels:    []string{"documentation"},
+					CreatedAt: "2025-01-03T10:00:00Z",
+					UpdatedAt: "2025-01-03T12:00:00Z",
+				},
+			},
+		},
+		{
+			name: "repo not found",
+			opts: Options{
+				URL: srv.URL + "/org/invalid",
+			},
+			wantErrMsg: "Unknown API Error: 404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewGiteaProvider(context.Background(), tt.opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListRequests(context.Background(), tt.opts)
+			if len(tt.wantErrMsg) > 0 {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(BeEquivalentTo(tt.want))
+		})
+	}
+}
+
+func TestGiteaProvider_ListRequests_RequestFilters(t *testing.T) {
+	srv := newGiteaTestServer(t)
+
+	tests := []struct {
+		name    string
+		filters Filters
+		want    []string
+	}{
+		{
+			name: "filters prs by base branch",
+			filters: Filters{
+				BaseBranchRe: regexp.MustCompile(`^main$`),
+			},
+			want: []string{"3", "1"},
+		},
+		{
+			name: "excludes draft and fork prs",
+			filters: Filters{
+				BaseBranchRe: regexp.MustCompile(`^main$`),
+				ExcludeDraft: true,
+				ExcludeForks: true,
+			},
+			want: []string{"3"},
+		},
+		{
+			name: "filters prs by author and max age",
+			filters: Filters{
+				IncludeAuthors: []string{"STEFANPRODAN"},
+				MaxAge:         time.Since(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)),
+			},
+			want: []string{"3", "2"},
+		},
+		{
+			name: "excludes prs by author",
+			filters: Filters{
+				ExcludeAuthors: []string{"stefanprodan"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts := Options{
+				URL:     srv.URL + "/org/app",
+				Filters: tt.filters,
+			}
+			provider, err := NewGiteaProvider(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListRequests(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var ids []string
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+			g.Expect(ids).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestParseGiteaURL(t *testing.T) {
+	g := NewWithT(t)
+
+	host, owner, repo, err := parseGiteaURL("https://codeberg.org/forgejo/forgejo")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(host).To(Equal("https://codeberg.org"))
+	g.Expect(owner).To(Equal("forgejo"))
+	g.Expect(repo).To(Equal("forgejo"))
+
+	host, owner, repo, err = parseGiteaURL("https://git.example.com/gitea/org/app.git")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(host).To(Equal("https://git.example.com/gitea"))
+	g.Expect(owner).To(Equal("org"))
+	g.Expect(repo).To(Equal("app"))
+
+	_, _, _, err = parseGiteaURL("https://codeberg.org/forgejo")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -9,8 +9,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/google/go-github/v69/github"
 	"golang.org/x/oauth2"
 )
@@ -19,47 +22,99 @@ type GitHubProvider struct {
 	Client *github.Client
 	Owner  string
 	Repo   string
+
+	transport *cachingTransport
+	resultLimiter
 }
 
 func NewGitHubProvider(ctx context.Context, opts Options) (*GitHubProvider, error) {
+	host, owner, repo, err := parseGitHubURL(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, transport, err := newGitHubClient(ctx, opts, host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitHubProvider{
+		Client:    client,
+		Owner:     owner,
+		Repo:      repo,
+		transport: transport,
+	}, nil
+}
+
+// NewGitHubOrganizationProvider returns a provider for the
+// repositories of the GitHub organization from the URL.
+func NewGitHubOrganizationProvider(ctx context.Context, opts Options) (*GitHubProvider, error) {
+	host, owner, err := parseGitHubOrganizationURL(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, transport, err := newGitHubClient(ctx, opts, host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitHubProvider{
+		Client:    client,
+		Owner:     owner,
+		transport: transport,
+	}, nil
+}
+
+// newGitHubClient returns a GitHub client for the given host
+// that makes conditional requests to the GitHub API.
+func newGitHubClient(ctx context.Context, opts Options, host string) (*github.Client, *cachingTransport, error) {
 	var client *github.Client
 	var ts oauth2.TokenSource
+	var err error
 
 	if opts.Token != "" {
 		ts = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: opts.Token})
 	}
 
-	host, owner, repo, err := parseGitHubURL(opts.URL)
-	if err != nil {
-		return nil, err
+	// Use conditional requests, with a custom cert pool for GitHub Enterprise.
+	var base http.RoundTripper
+	if opts.CertPool != nil && host != "https://github.com" {
+		base = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs: opts.CertPool,
+			},
+		}
 	}
+	transport := newCachingTransport(base)
+	ctxTr := context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
+	httpClient := oauth2.NewClient(ctxTr, ts)
 
 	if host == "https://github.com" {
 		// Create a GitHub client for GitHub.com
-		client = github.NewClient(oauth2.NewClient(ctx, ts))
+		client = github.NewClient(httpClient)
 	} else {
-		// Create a GitHub client for GitHub Enterprise with a custom cert pool.
-		var httpClient *http.Client
-		if opts.CertPool != nil {
-			tr := &http.Transport{
-				TLSClientConfig: &tls.Config{
-					RootCAs: opts.CertPool,
-				},
-			}
-			ctxCA := context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: tr})
-			httpClient = oauth2.NewClient(ctxCA, ts)
-		}
+		// Create a GitHub client for GitHub Enterprise.
 		client, err = github.NewClient(httpClient).WithEnterpriseURLs(host, host)
 		if err != nil {
-			return nil, fmt.Errorf("could not create enterprise GitHub client: %v", err)
+			return nil, nil, fmt.Errorf("could not create enterprise GitHub client: %v", err)
 		}
 	}
 
-	return &GitHubProvider{
-		Client: client,
-		Owner:  owner,
-		Repo:   repo,
-	}, nil
+	return client, transport, nil
+}
+
+// RepositoryPath returns the repository path in the 'owner/repo' format.
+func (p *GitHubProvider) RepositoryPath() string {
+	return p.Owner + "/" + p.Repo
+}
+
+// RateLimit returns the GitHub API rate limit status from the last response.
+func (p *GitHubProvider) RateLimit() *RateLimit {
+	if p.transport == nil {
+		return nil
+	}
+	return p.transport.RateLimit()
 }
 
 func (p *GitHubProvider) ListBranches(ctx context.Context, opts Options) ([]Result, error) {
@@ -86,10 +141,6 @@ func (p *GitHubProvider) ListBranches(ctx context.Context, opts Options) ([]Resu
 				SHA:    branch.GetCommit().GetSHA(),
 				Branch: branch.GetName(),
 			})
-
-			if opts.Filters.Limit > 0 && len(results) >= opts.Filters.Limit {
-				return results, nil
-			}
 		}
 
 		if resp.NextPage == 0 {
@@ -98,7 +149,7 @@ func (p *GitHubProvider) ListBranches(ctx context.Context, opts Options) ([]Resu
 		ghOpts.Page = resp.NextPage
 	}
 
-	return results, nil
+	return p.limit(opts, results), nil
 }
 
 func (p *GitHubProvider) ListRequests(ctx context.Context, opts Options) ([]Result, error) {
@@ -130,18 +181,85 @@ func (p *GitHubProvider) ListRequests(ctx context.Context, opts Options) ([]Resu
 				continue
 			}
 
+			if !matchRequest(opts, requestInfo{
+				BaseBranch: pr.GetBase().GetRef(),
+				Author:     pr.GetUser().GetLogin(),
+				Draft:      pr.GetDraft(),
+				Fork:       pr.GetHead().GetRepo().GetFullName() != pr.GetBase().GetRepo().GetFullName(),
+				CreatedAt:  pr.GetCreatedAt().Time,
+			}) {
+				continue
+			}
+
 			results = append(results, Result{
-				ID:     fmt.Sprintf("%d", pr.GetNumber()),
-				SHA:    pr.GetHead().GetSHA(),
-				Branch: pr.GetHead().GetRef(),
-				Title:  pr.GetTitle(),
-				Author: pr.GetUser().GetLogin(),
-				Labels: prLabels,
+				ID:             fmt.Sprintf("%d", pr.GetNumber()),
+				SHA:            pr.GetHead().GetSHA(),
+				Branch:         pr.GetHead().GetRef(),
+				Title:          pr.GetTitle(),
+				Author:         pr.GetUser().GetLogin(),
+				Labels:         prLabels,
+				Number:         pr.GetNumber(),
+				URL:            pr.GetHTMLURL(),
+				BaseBranch:     pr.GetBase().GetRef(),
+				HeadRepository: pr.GetHead().GetRepo().GetFullName(),
+				Draft:          pr.GetDraft(),
+				CreatedAt:      formatTime(pr.GetCreatedAt().Time),
+				UpdatedAt:      formatTime(pr.GetUpdatedAt().Time),
 			})
+		}
 
-			if opts.Filters.Limit > 0 && len(results) >= opts.Filters.Limit {
-				return results, nil
+		if resp.NextPage == 0 {
+			break
+		}
+		ghOpts.Page = resp.NextPage
+	}
+
+	results = p.limit(opts, results)
+
+	// Fetch the commit timestamp of the pull requests head when enabled,
+	// the commits of the forks are available in the base repository.
+	// The timestamp is left empty if the commit can't be fetched,
+	// unless the request is rate limited or forbidden.
+	if opts.CommitTimestamp {
+		for i := range results {
+			commit, resp, err := p.Client.Git.GetCommit(ctx, p.Owner, p.Repo, results[i].SHA)
+			if err != nil {
+				var httpResp *http.Response
+				if resp != nil {
+					httpResp = resp.Response
+				}
+				if isFatalLookupError(ctx, httpResp) {
+					return nil, fmt.Errorf("could not get commit %s: %w", results[i].SHA, err)
+				}
+				logr.FromContextOrDiscard(ctx).Error(err, "failed to get the pull request head commit",
+					"number", results[i].Number, "sha", results[i].SHA)
+				continue
 			}
+			results[i].CommitTimestamp = formatTime(commit.GetCommitter().GetDate().Time)
+		}
+	}
+
+	return results, nil
+}
+
+func (p *GitHubProvider) ListTags(ctx context.Context, opts Options) ([]Result, error) {
+	ghOpts := &github.ListOptions{
+		PerPage: 100,
+	}
+
+	var results []Result
+	for {
+		tags, resp, err := p.Client.Repositories.ListTags(ctx, p.Owner, p.Repo, ghOpts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list tags: %v", err)
+		}
+
+		for _, tag := range tags {
+			results = append(results, Result{
+				ID:  checksum(tag.GetName()),
+				SHA: tag.GetCommit().GetSHA(),
+				Tag: tag.GetName(),
+			})
 		}
 
 		if resp.NextPage == 0 {
@@ -150,7 +268,214 @@ func (p *GitHubProvider) ListRequests(ctx context.Context, opts Options) ([]Resu
 		ghOpts.Page = resp.NextPage
 	}
 
-	return results, nil
+	return p.limit(opts, filterTags(opts, results, false)), nil
+}
+
+// ReportStatus creates a commit status for the given SHA, unless the latest
+// status with the same context has the same state and description.
+func (p *GitHubProvider) ReportStatus(ctx context.Context, sha string, status CommitStatus) error {
+	combined, _, err := p.Client.Repositories.GetCombinedStatus(ctx, p.Owner, p.Repo, sha, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return fmt.Errorf("could not get commit status %s: %v", sha, err)
+	}
+
+	for _, s := range combined.Statuses {
+		if s.GetContext() == status.Context &&
+			s.GetState() == string(status.State) &&
+			s.GetDescription() == status.description() {
+			return nil
+		}
+	}
+
+	repoStatus := &github.RepoStatus{
+		State:       github.Ptr(string(status.State)),
+		Context:     github.Ptr(status.Context),
+		Description: github.Ptr(status.description()),
+	}
+	if status.TargetURL != "" {
+		repoStatus.TargetURL = github.Ptr(status.TargetURL)
+	}
+
+	if _, _, err := p.Client.Repositories.CreateStatus(ctx, p.Owner, p.Repo, sha, repoStatus); err != nil {
+		return fmt.Errorf("could not create commit status %s: %v", sha, err)
+	}
+
+	return nil
+}
+
+// LabeledBy returns the user that last applied the label
+// to the pull request, based on the issue events.
+func (p *GitHubProvider) LabeledBy(ctx context.Context, number int, label string) (string, time.Time, error) {
+	ghOpts := &github.ListOptions{PerPage: 100}
+
+	var user string
+	var at time.Time
+	for {
+		events, resp, err := p.Client.Issues.ListIssueEvents(ctx, p.Owner, p.Repo, number, ghOpts)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("could not list events of pull request %d: %v", number, err)
+		}
+
+		for _, event := range events {
+			if event.GetEvent() == "labeled" && event.GetLabel().GetName() == label {
+				user = event.GetActor().GetLogin()
+				at = event.GetCreatedAt().Time
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		ghOpts.Page = resp.NextPage
+	}
+
+	return user, at, nil
+}
+
+// ListApprovers returns the users whose latest review of the pull request is
+// an approval. When the SHA is set, only the approvals of that commit count.
+func (p *GitHubProvider) ListApprovers(ctx context.Context, number int, sha string) ([]string, error) {
+	ghOpts := &github.ListOptions{PerPage: 100}
+
+	latest := make(map[string]*github.PullRequestReview)
+	for {
+		reviews, resp, err := p.Client.PullRequests.ListReviews(ctx, p.Owner, p.Repo, number, ghOpts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list reviews of pull request %d: %v", number, err)
+		}
+
+		// The reviews are returned in chronological order, and the
+		// comments don't change the approval state of the reviewer.
+		for _, review := range reviews {
+			if review.GetState() != "COMMENTED" {
+				latest[review.GetUser().GetLogin()] = review
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		ghOpts.Page = resp.NextPage
+	}
+
+	var approvers []string
+	for user, review := range latest {
+		if review.GetState() == "APPROVED" && (sha == "" || review.GetCommitID() == sha) {
+			approvers = append(approvers, user)
+		}
+	}
+	slices.Sort(approvers)
+
+	return approvers, nil
+}
+
+// ReadFile resolves the ref to a commit SHA and returns
+// the content of the file at the given path in that commit.
+func (p *GitHubProvider) ReadFile(ctx context.Context, path, ref string) ([]byte, string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	sha, _, err := p.Client.Repositories.GetCommitSHA1(ctx, p.Owner, p.Repo, ref, "")
+	if err != nil {
+		return nil, "", fmt.Errorf("could not resolve ref %s: %v", ref, err)
+	}
+
+	file, _, _, err := p.Client.Repositories.GetContents(ctx, p.Owner, p.Repo, path,
+		&github.RepositoryContentGetOptions{Ref: sha})
+	if err != nil {
+		return nil, "", fmt.Errorf("could not get file %s: %v", path, err)
+	}
+	if file == nil {
+		return nil, "", fmt.Errorf("could not get file %s: path is a directory", path)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, "", fmt.Errorf("could not decode file %s: %v", path, err)
+	}
+
+	return []byte(content), sha, nil
+}
+
+// ListRepositories returns the repositories of the organization
+// sorted by name, excluding the archived repositories by default.
+func (p *GitHubProvider) ListRepositories(ctx context.Context, opts Options) ([]Result, error) {
+	ghOpts := &github.RepositoryListByOrgOptions{
+		Sort: "full_name",
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	var results []Result
+	for {
+		repos, resp, err := p.Client.Repositories.ListByOrg(ctx, p.Owner, ghOpts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list repositories: %v", err)
+		}
+
+		for _, repo := range repos {
+			if !matchRepository(opts, repositoryInfo{
+				Topics:     repo.Topics,
+				Visibility: githubVisibility(repo),
+				Archived:   repo.GetArchived(),
+			}) {
+				continue
+			}
+
+			results = append(results, Result{
+				ID:             checksum(repo.GetFullName()),
+				Repository:     repo.GetName(),
+				RepositoryPath: repo.GetFullName(),
+				URL:            repo.GetHTMLURL(),
+				CloneURL:       repo.GetCloneURL(),
+				DefaultBranch:  repo.GetDefaultBranch(),
+				Topics:         repo.Topics,
+				CreatedAt:      formatTime(repo.GetCreatedAt().Time),
+				UpdatedAt:      formatTime(repo.GetUpdatedAt().Time),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		ghOpts.Page = resp.NextPage
+	}
+
+	return p.limit(opts, results), nil
+}
+
+// githubVisibility returns the visibility of the repository, falling back
+// to the private flag for the GitHub Enterprise versions that don't report it.
+func githubVisibility(repo *github.Repository) string {
+	if v := repo.GetVisibility(); v != "" {
+		return v
+	}
+	if repo.GetPrivate() {
+		return "private"
+	}
+	return "public"
+}
+
+// GitHubAppBaseURL returns the GitHub API endpoint used to fetch the GitHub App
+// installation tokens for the given repository URL. For github.com, an empty
+// string is returned, and the default API endpoint must be used.
+func GitHubAppBaseURL(ghURL string) (string, error) {
+	// The URL can point to a repository or to an organization.
+	host, _, _, err := parseGitHubURL(ghURL)
+	if err != nil {
+		var orgErr error
+		if host, _, orgErr = parseGitHubOrganizationURL(ghURL); orgErr != nil {
+			return "", err
+		}
+	}
+
+	if host == "https://github.com" {
+		return "", nil
+	}
+
+	return host + "/api/v3", nil
 }
 
 // parseGitHubURL parses a GitHub URL and returns the host, owner, and repo.
@@ -167,3 +492,18 @@ func parseGitHubURL(ghURL string) (string, string, string, error) {
 
 	return fmt.Sprintf("%s://%s", u.Scheme, u.Host), parts[0], parts[1], nil
 }
+
+// parseGitHubOrganizationURL parses a GitHub organization URL and returns the host and owner.
+func parseGitHubOrganizationURL(ghURL string) (string, string, error) {
+	u, err := url.Parse(ghURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid URL %q: %w", ghURL, err)
+	}
+
+	owner := strings.Trim(u.Path, "/")
+	if owner == "" || strings.Contains(owner, "/") {
+		return "", "", fmt.Errorf("invalid GitHub URL %q: can't find organization", ghURL)
+	}
+
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host), owner, nil
+}
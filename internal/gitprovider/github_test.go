@@ -5,9 +5,14 @@ package gitprovider
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 )
@@ -110,44 +115,59 @@ func TestGitHubProvider_ListRequests(t *testing.T) {
 			},
 			want: []Result{
 				{
-					ID:     "5",
-					SHA:    "f43c54d06a19335cb8be4607ef9a05a3b20fb485",
-					Title:  "test5: Update README.md",
-					Author: "stefanprodan",
-					Branch: "feat/5",
-					Labels: []string{},
+					ID:             "5",
+					SHA:            "f43c54d06a19335cb8be4607ef9a05a3b20fb485",
+					Title:          "test5: Update README.md",
+					Author:         "stefanprodan",
+					Branch:         "feat/5",
+					Labels:         []string{},
+					Number:         5,
+					URL:            "https://github.com/fluxcd-testing/pr-testing/pull/5",
+					HeadRepository: "fluxcd-testing/pr-testing",
 				},
 				{
-					ID:     "4",
-					SHA:    "80332195632fe293564ff563344032cf4c75af45",
-					Title:  "test4: Update README.md",
-					Author: "stefanprodan",
-					Branch: "stefanprodan-patch-4",
-					Labels: []string{"documentation", "enhancement"},
+					ID:             "4",
+					SHA:            "80332195632fe293564ff563344032cf4c75af45",
+					Title:          "test4: Update README.md",
+					Author:         "stefanprodan",
+					Branch:         "stefanprodan-patch-4",
+					Labels:         []string{"documentation", "enhancement"},
+					Number:         4,
+					URL:            "https://github.com/fluxcd-testing/pr-testing/pull/4",
+					HeadRepository: "fluxcd-testing/pr-testing",
 				},
 				{
-					ID:     "3",
-					SHA:    "29d1d3a726e1e1f68b7cb60ac891cb83fa260ea9",
-					Title:  "test3: Update README.md",
-					Author: "stefanprodan",
-					Branch: "stefanprodan-patch-3",
-					Labels: []string{"documentation"},
+					ID:             "3",
+					SHA:            "29d1d3a726e1e1f68b7cb60ac891cb83fa260ea9",
+					Title:          "test3: Update README.md",
+					Author:         "stefanprodan",
+					Branch:         "stefanprodan-patch-3",
+					Labels:         []string{"documentation"},
+					Number:         3,
+					URL:            "https://github.com/fluxcd-testing/pr-testing/pull/3",
+					HeadRepository: "fluxcd-testing/pr-testing",
 				},
 				{
-					ID:     "2",
-					SHA:    "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
-					Title:  "test2: Update README.md",
-					Author: "stefanprodan",
-					Branch: "stefanprodan-patch-2",
-					Labels: []string{"enhancement"},
+					ID:             "2",
+					SHA:            "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
+					Title:          "test2: Update README.md",
+					Author:         "stefanprodan",
+					Branch:         "stefanprodan-patch-2",
+					Labels:         []string{"enhancement"},
+					Number:         2,
+					URL:            "https://github.com/fluxcd-testing/pr-testing/pull/2",
+					HeadRepository: "fluxcd-testing/pr-testing",
 				},
 				{
-					ID:     "1",
-					SHA:    "2dd3a8d2088457e5cf991018edf13e25cbd61380",
-					Title:  "test1: Update README.md",
-					Author: "stefanprodan",
-					Branch: "stefanprodan-patch-1",
-					Labels: []string{"enhancement"},
+					ID:             "1",
+					SHA:            "2dd3a8d2088457e5cf991018edf13e25cbd61380",
+					Title:          "test1: Update README.md",
+					Author:         "stefanprodan",
+					Branch:         "stefanprodan-patch-1",
+					Labels:         []string{"enhancement"},
+					Number:         1,
+					URL:            "https://github.com/fluxcd-testing/pr-testing/pull/1",
+					HeadRepository: "fluxcd-testing/pr-testing",
 				},
 			},
 		},
@@ -163,20 +183,26 @@ func TestGitHubProvider_ListRequests(t *testing.T) {
 			},
 			want: []Result{
 				{
-					ID:     "4",
-					SHA:    "80332195632fe293564ff563344032cf4c75af45",
-					Title:  "test4: Update README.md",
-					Author: "stefanprodan",
-					Branch: "stefanprodan-patch-4",
-					Labels: []string{"documentation", "enhancement"},
+					ID:             "4",
+					SHA:            "80332195632fe293564ff563344032cf4c75af45",
+					Title:          "test4: Update README.md",
+					Author:         "stefanprodan",
+					Branch:         "stefanprodan-patch-4",
+					Labels:         []string{"documentation", "enhancement"},
+					Number:         4,
+					URL:            "https://github.com/fluxcd-testing/pr-testing/pull/4",
+					HeadRepository: "fluxcd-testing/pr-testing",
 				},
 				{
-					ID:     "2",
-					SHA:    "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
-					Title:  "test2: Update README.md",
-					Author: "stefanprodan",
-					Branch: "stefanprodan-patch-2",
-					Labels: []string{"enhancement"},
+					ID:             "2",
+					SHA:            "1e5aef14d38a8c67e5240308adf2935d6cdc2ec8",
+					Title:          "test2: Update README.md",
+					Author:         "stefanprodan",
+					Branch:         "stefanprodan-patch-2",
+					Labels:         []string{"enhancement"},
+					Number:         2,
+					URL:            "https://github.com/fluxcd-testing/pr-testing/pull/2",
+					HeadRepository: "fluxcd-testing/pr-testing",
 				},
 			},
 		},
@@ -191,12 +217,15 @@ func TestGitHubProvider_ListRequests(t *testing.T) {
 			},
 			want: []Result{
 				{
-					ID:     "5",
-					SHA:    "f43c54d06a19335cb8be4607ef9a05a3b20fb485",
-					Title:  "test5: Update README.md",
-					Author: "stefanprodan",
-					Branch: "feat/5",
-					Labels: []string{},
+					ID:             "5",
+					SHA:            "f43c54d06a19335cb8be4607ef9a05a3b20fb485",
+					Title:          "test5: Update README.md",
+					Author:         "stefanprodan",
+					Branch:         "feat/5",
+					Labels:         []string{},
+					Number:         5,
+					URL:            "https://github.com/fluxcd-testing/pr-testing/pull/5",
+					HeadRepository: "fluxcd-testing/pr-testing",
 				},
 			},
 		},
@@ -232,7 +261,579 @@ func TestGitHubProvider_ListRequests(t *testing.T) {
 			}
 			g.Expect(err).NotTo(HaveOccurred())
 
-			g.Expect(got).To(BeEquivalentTo(tt.want))
+			g.Expect(stripRequestTimes(g, got)).To(BeEquivalentTo(tt.want))
+		})
+	}
+}
+
+func TestGitHubProvider_Enterprise(t *testing.T) {
+	g := NewWithT(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/fluxcd-testing/pr-testing/branches", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name":"main","commit":{"sha":"a1b2c3d4e5f60718293a4b5c6d7e8f9001122334"}}]`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	opts := Options{
+		Token: "test-token",
+		URL:   srv.URL + "/fluxcd-testing/pr-testing",
+	}
+
+	provider, err := NewGitHubProvider(context.Background(), opts)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	got, err := provider.ListBranches(context.Background(), opts)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal([]Result{
+		{
+			ID:     "68878758",
+			SHA:    "a1b2c3d4e5f60718293a4b5c6d7e8f9001122334",
+			Branch: "main",
+		},
+	}))
+}
+
+func TestGitHubProvider_ListRequests_Filters(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/fluxcd-testing/pr-testing/pulls", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+{"number":4,"title":"draft","draft":true,"user":{"login":"alice"},"created_at":"2099-01-01T00:00:00Z",
+ "updated_at":"2099-01-03T00:00:00Z","html_url":"https://github.example.com/fluxcd-testing/pr-testing/pull/4",
+ "head":{"ref":"feat-4","sha":"a4","repo":{"full_name":"fluxcd-testing/pr-testing"}},
+ "base":{"ref":"main","repo":{"full_name":"fluxcd-testing/pr-testing"}}},
+{"number":3,"title":"fork","user":{"login":"mallory"},"created_at":"2099-01-01T00:00:00Z",
+ "head":{"ref":"feat-3","sha":"a3","repo":{"full_name":"mallory/pr-testing"}},
+ "base":{"ref":"main","repo":{"full_name":"fluxcd-testing/pr-testing"}}},
+{"number":2,"title":"release","user":{"login":"bob"},"created_at":"2099-01-01T00:00:00Z",
+ "head":{"ref":"feat-2","sha":"a2","repo":{"full_name":"fluxcd-testing/pr-testing"}},
+ "base":{"ref":"release/v1","repo":{"full_name":"fluxcd-testing/pr-testing"}}},
+{"number":1,"title":"old","user":{"login":"alice"},"created_at":"2020-01-01T00:00:00Z",
+ "head":{"ref":"feat-1","sha":"a1","repo":{"full_name":"fluxcd-testing/pr-testing"}},
+ "base":{"ref":"main","repo":{"full_name":"fluxcd-testing/pr-testing"}}}
+]`))
+	})
+	mux.HandleFunc("/api/v3/repos/fluxcd-testing/pr-testing/git/commits/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/a1") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"committer":{"date":"2099-01-02T10:00:00+02:00"}}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name                string
+		filters             Filters
+		skipCommitTimestamp bool
+		want                []string
+		wantDropped         int
+	}{
+		{
+			name: "no filters",
+			want: []string{"4", "3", "2", "1"},
+		},
+		{
+			name:                "skips the commit timestamp lookup",
+			skipCommitTimestamp: true,
+			want:                []string{"4", "3", "2", "1"},
+		},
+		{
+			name: "filters by base branch",
+			filters: Filters{
+				BaseBranchRe: regexp.MustCompile(`^release/`),
+			},
+			want: []string{"2"},
+		},
+		{
+			name: "excludes drafts and forks",
+			filters: Filters{
+				ExcludeDraft: true,
+				ExcludeForks: true,
+			},
+			want: []string{"2", "1"},
+		},
+		{
+			name: "filters by authors",
+			filters: Filters{
+				IncludeAuthors: []string{"alice", "bob"},
+				ExcludeAuthors: []string{"bob"},
+			},
+			want: []string{"4", "1"},
+		},
+		{
+			name: "filters by max age",
+			filters: Filters{
+				MaxAge: 24 * time.Hour,
+			},
+			want: []string{"4", "3", "2"},
+		},
+		{
+			name: "sorts by created before the limit",
+			filters: Filters{
+				SortBy: SortByCreated,
+				Limit:  3,
+			},
+			want:        []string{"1", "2", "3"},
+			wantDropped: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts := Options{
+				URL:             srv.URL + "/fluxcd-testing/pr-testing",
+				Filters:         tt.filters,
+				CommitTimestamp: !tt.skipCommitTimestamp,
+			}
+
+			provider, err := NewGitHubProvider(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListRequests(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			ids := make([]string, 0, len(got))
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+			g.Expect(ids).To(Equal(tt.want))
+			g.Expect(provider.Dropped()).To(Equal(tt.wantDropped))
+
+			for _, r := range got {
+				// The commit of the pull request 1 can't be fetched.
+				if tt.skipCommitTimestamp || r.ID == "1" {
+					g.Expect(r.CommitTimestamp).To(BeEmpty())
+					continue
+				}
+				g.Expect(r.CommitTimestamp).To(Equal("2099-01-02T08:00:00Z"))
+				if r.ID == "4" {
+					g.Expect(r).To(Equal(Result{
+						ID:              "4",
+						SHA:             "a4",
+						Branch:          "feat-4",
+						Title:           "draft",
+						Author:          "alice",
+						Labels:          []string{},
+						Number:          4,
+						URL:             "https://github.example.com/fluxcd-testing/pr-testing/pull/4",
+						BaseBranch:      "main",
+						HeadRepository:  "fluxcd-testing/pr-testing",
+						CommitTimestamp: "2099-01-02T08:00:00Z",
+						Draft:           true,
+						CreatedAt:       "2099-01-01T00:00:00Z",
+						UpdatedAt:       "2099-01-03T00:00:00Z",
+					}))
+				}
+				if r.ID == "3" {
+					g.Expect(r.HeadRepository).To(Equal("mallory/pr-testing"))
+				}
+			}
+		})
+	}
+}
+
+func TestGitHubProvider_ListRequests_CommitLookupErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErrMsg string
+	}{
+		{
+			name:       "ignores the lookup errors",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "fails on rate limited lookups",
+			statusCode: http.StatusTooManyRequests,
+			wantErrMsg: "could not get commit a1",
+		},
+		{
+			name:       "fails on forbidden lookups",
+			statusCode: http.StatusForbidden,
+			wantErrMsg: "could not get commit a1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/repos/fluxcd-testing/pr-testing/pulls", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[{"number":1,"user":{"login":"alice"},"head":{"ref":"feat-1","sha":"a1"}}]`))
+			})
+			mux.HandleFunc("/api/v3/repos/fluxcd-testing/pr-testing/git/commits/a1", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			opts := Options{
+				URL:             srv.URL + "/fluxcd-testing/pr-testing",
+				CommitTimestamp: true,
+			}
+			provider, err := NewGitHubProvider(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListRequests(context.Background(), opts)
+			if tt.wantErrMsg != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(HaveLen(1))
+			g.Expect(got[0].CommitTimestamp).To(BeEmpty())
+		})
+	}
+}
+
+func TestGitHubProvider_ReportStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     CommitStatus
+		wantCreate map[string]any
+	}{
+		{
+			name: "skips unchanged status",
+			status: CommitStatus{
+				Context:     "flux-operator/apps/preview",
+				State:       CommitStatusSuccess,
+				Description: "Reconciliation succeeded",
+			},
+		},
+		{
+			name: "creates status for new state",
+			status: CommitStatus{
+				Context:     "flux-operator/apps/preview",
+				State:       CommitStatusFailure,
+				Description: "reconciliation failed",
+			},
+			wantCreate: map[string]any{
+				"context":     "flux-operator/apps/preview",
+				"state":       "failure",
+				"description": "reconciliation failed",
+			},
+		},
+		{
+			name: "creates status for new context",
+			status: CommitStatus{
+				Context:     "flux-operator/apps/other",
+				State:       CommitStatusSuccess,
+				Description: "Reconciliation succeeded",
+				TargetURL:   "https://flux.example.com",
+			},
+			wantCreate: map[string]any{
+				"context":     "flux-operator/apps/other",
+				"state":       "success",
+				"description": "Reconciliation succeeded",
+				"target_url":  "https://flux.example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var created map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v3/repos/fluxcd-testing/pr-testing/commits/a1/status", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"state":"success","statuses":[
+{"context":"flux-operator/apps/preview","state":"success","description":"Reconciliation succeeded"}]}`))
+			})
+			mux.HandleFunc("POST /api/v3/repos/fluxcd-testing/pr-testing/statuses/a1", func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(json.NewDecoder(r.Body).Decode(&created)).To(Succeed())
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{}`))
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			provider, err := NewGitHubProvider(context.Background(), Options{
+				URL: srv.URL + "/fluxcd-testing/pr-testing",
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			err = provider.ReportStatus(context.Background(), "a1", tt.status)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(created).To(Equal(tt.wantCreate))
+		})
+	}
+}
+
+func TestGitHubProvider_ListRepositories(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters Filters
+		want    []string
+	}{
+		{
+			name: "excludes archived by default",
+			want: []string{"app1", "app2", "infra"},
+		},
+		{
+			name:    "includes archived",
+			filters: Filters{IncludeArchived: true},
+			want:    []string{"app1", "app2", "infra", "legacy"},
+		},
+		{
+			name:    "matches all topics",
+			filters: Filters{Topics: []string{"flux", "app"}},
+			want:    []string{"app1", "app2"},
+		},
+		{
+			name:    "matches visibility",
+			filters: Filters{Visibility: "Public", IncludeArchived: true},
+			want:    []string{"app1", "legacy"},
+		},
+		{
+			name:    "matches private visibility",
+			filters: Filters{Visibility: "private"},
+			want:    []string{"infra"},
+		},
+		{
+			name:    "limits results",
+			filters: Filters{Limit: 1},
+			want:    []string{"app1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v3/orgs/fluxcd-testing/repos", func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.URL.Query().Get("sort")).To(Equal("full_name"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[
+{"name":"app1","full_name":"fluxcd-testing/app1","html_url":"https://github.example.com/fluxcd-testing/app1",
+ "clone_url":"https://github.example.com/fluxcd-testing/app1.git","default_branch":"main",
+ "topics":["flux","app"],"visibility":"public","created_at":"2024-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"},
+{"name":"app2","full_name":"fluxcd-testing/app2","default_branch":"master",
+ "topics":["app","flux"],"visibility":"internal"},
+{"name":"infra","full_name":"fluxcd-testing/infra","default_branch":"main",
+ "topics":["flux"],"private":true},
+{"name":"legacy","full_name":"fluxcd-testing/legacy","default_branch":"main",
+ "visibility":"public","archived":true}]`))
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			provider, err := NewGitHubOrganizationProvider(context.Background(), Options{
+				URL: srv.URL + "/fluxcd-testing",
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			results, err := provider.ListRepositories(context.Background(), Options{Filters: tt.filters})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			for _, r := range results {
+				names = append(names, r.Repository)
+			}
+			g.Expect(names).To(Equal(tt.want))
+
+			if names[0] != "app1" {
+				return
+			}
+
+			// The first repository exports all the fields.
+			g.Expect(results[0]).To(Equal(Result{
+				ID:             checksum("fluxcd-testing/app1"),
+				Repository:     "app1",
+				RepositoryPath: "fluxcd-testing/app1",
+				URL:            "https://github.example.com/fluxcd-testing/app1",
+				CloneURL:       "https://github.example.com/fluxcd-testing/app1.git",
+				DefaultBranch:  "main",
+				Topics:         []string{"flux", "app"},
+				CreatedAt:      "2024-01-01T00:00:00Z",
+				UpdatedAt:      "2025-01-01T00:00:00Z",
+			}))
 		})
 	}
+
+	t.Run("invalid organization URL", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := NewGitHubOrganizationProvider(context.Background(), Options{
+			URL: "https://github.com/fluxcd-testing/pr-testing",
+		})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("can't find organization"))
+	})
+}
+
+func TestGitHubAppBaseURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		want       string
+		wantErrMsg string
+	}{
+		{
+			name: "github.com",
+			url:  "https://github.com/fluxcd-testing/pr-testing",
+			want: "",
+		},
+		{
+			name: "GitHub Enterprise",
+			url:  "https://github.example.com/fluxcd-testing/pr-testing",
+			want: "https://github.example.com/api/v3",
+		},
+		{
+			name: "GitHub Enterprise organization",
+			url:  "https://github.example.com/fluxcd-testing",
+			want: "https://github.example.com/api/v3",
+		},
+		{
+			name:       "invalid URL",
+			url:        "https://github.example.com/fluxcd-testing/pr-testing/pulls",
+			wantErrMsg: "can't find owner and repository",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := GitHubAppBaseURL(tt.url)
+			if tt.wantErrMsg != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGitHubProvider_ReadFile(t *testing.T) {
+	const sha = "a1b2c3d4e5f60718293a4b5c6d7e8f9001122334"
+
+	tests := []struct {
+		name       string
+		ref        string
+		wantRef    string
+		wantErrMsg string
+	}{
+		{
+			name:    "reads from the default branch",
+			wantRef: "HEAD",
+		},
+		{
+			name:    "reads from ref",
+			ref:     "v1.0.0",
+			wantRef: "v1.0.0",
+		},
+		{
+			name:       "fails for unknown ref",
+			ref:        "unknown",
+			wantErrMsg: "could not resolve ref unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v3/repos/fluxcd-testing/pr-testing/commits/{ref}", func(w http.ResponseWriter, r *http.Request) {
+				if r.PathValue("ref") != tt.wantRef {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					return
+				}
+				_, _ = w.Write([]byte(sha))
+			})
+			mux.HandleFunc("GET /api/v3/repos/fluxcd-testing/pr-testing/contents/envs/inputs.yaml", func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.URL.Query().Get("ref")).To(Equal(sha))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"type":"file","encoding":"base64","content":"LSBlbnY6IHN0YWdpbmcK"}`))
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			provider, err := NewGitHubProvider(context.Background(), Options{
+				URL: srv.URL + "/fluxcd-testing/pr-testing",
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			content, revision, err := provider.ReadFile(context.Background(), "envs/inputs.yaml", tt.ref)
+			if tt.wantErrMsg != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(content)).To(Equal("- env: staging\n"))
+			g.Expect(revision).To(Equal(sha))
+		})
+	}
+}
+
+func TestGitHubProvider_Approvals(t *testing.T) {
+	g := NewWithT(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/fluxcd-testing/pr-testing/issues/4/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+{"event":"labeled","actor":{"login":"mallory"},"label":{"name":"deploy"},"created_at":"2025-04-01T10:00:00Z"},
+{"event":"unlabeled","actor":{"login":"alice"},"label":{"name":"deploy"},"created_at":"2025-04-01T11:00:00Z"},
+{"event":"labeled","actor":{"login":"alice"},"label":{"name":"deploy"},"created_at":"2025-04-02T10:00:00Z"},
+{"event":"labeled","actor":{"login":"bob"},"label":{"name":"bug"},"created_at":"2025-04-03T10:00:00Z"}]`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/fluxcd-testing/pr-testing/pulls/4/reviews", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+{"user":{"login":"alice"},"state":"APPROVED","commit_id":"a1"},
+{"user":{"login":"bob"},"state":"APPROVED","commit_id":"a1"},
+{"user":{"login":"bob"},"state":"CHANGES_REQUESTED","commit_id":"a2"},
+{"user":{"login":"carol"},"state":"APPROVED","commit_id":"a2"},
+{"user":{"login":"carol"},"state":"COMMENTED","commit_id":"a2"}]`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	provider, err := NewGitHubProvider(context.Background(), Options{
+		URL: srv.URL + "/fluxcd-testing/pr-testing",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	user, at, err := provider.LabeledBy(context.Background(), 4, "deploy")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(user).To(Equal("alice"))
+	g.Expect(at).To(BeTemporally("==", time.Date(2025, 4, 2, 10, 0, 0, 0, time.UTC)))
+
+	user, _, err = provider.LabeledBy(context.Background(), 4, "approved")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(user).To(BeEmpty())
+
+	approvers, err := provider.ListApprovers(context.Background(), 4, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(approvers).To(Equal([]string{"alice", "carol"}))
+
+	approvers, err = provider.ListApprovers(context.Background(), 4, "a2")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(approvers).To(Equal([]string{"carol"}))
 }
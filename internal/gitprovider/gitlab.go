@@ -6,11 +6,15 @@ package gitprovider
 import (
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-retryablehttp"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
@@ -18,17 +22,55 @@ import (
 type GitLabProvider struct {
 	Client  *gitlab.Client
 	Project string
+	Group   string
+
+	transport *cachingTransport
+	resultLimiter
 }
 
 func NewGitLabProvider(ctx context.Context, opts Options) (*GitLabProvider, error) {
-	var client *gitlab.Client
-	var glOpts []gitlab.ClientOptionFunc
-
 	host, project, err := parseGitLabURL(opts.URL)
 	if err != nil {
 		return nil, err
 	}
 
+	client, transport, err := newGitLabClient(opts, host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitLabProvider{
+		Client:    client,
+		Project:   project,
+		transport: transport,
+	}, nil
+}
+
+// NewGitLabGroupProvider returns a provider for the
+// projects of the GitLab group from the URL.
+func NewGitLabGroupProvider(ctx context.Context, opts Options) (*GitLabProvider, error) {
+	host, group, err := parseGitLabURL(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, transport, err := newGitLabClient(opts, host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitLabProvider{
+		Client:    client,
+		Group:     group,
+		transport: transport,
+	}, nil
+}
+
+// newGitLabClient returns a GitLab client for the given host
+// that makes conditional requests to the GitLab API.
+func newGitLabClient(opts Options, host string) (*gitlab.Client, *cachingTransport, error) {
+	var glOpts []gitlab.ClientOptionFunc
+
 	rtClient := retryablehttp.NewClient()
 	if opts.CertPool != nil {
 		tr := &http.Transport{
@@ -38,21 +80,48 @@ func NewGitLabProvider(ctx context.Context, opts Options) (*GitLabProvider, erro
 		}
 		rtClient.HTTPClient.Transport = tr
 	}
+
+	// Use conditional requests and record the rate limit status.
+	transport := newCachingTransport(rtClient.HTTPClient.Transport)
+	rtClient.HTTPClient.Transport = transport
 	glOpts = append(glOpts, gitlab.WithHTTPClient(rtClient.HTTPClient))
 
+	// Retry only the server errors, the rate limited requests
+	// are retried by requeuing the reconciliation at the reset time.
+	glOpts = append(glOpts, gitlab.WithCustomRetry(
+		func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			if err != nil {
+				return false, err
+			}
+			return resp.StatusCode >= http.StatusInternalServerError, nil
+		}))
+
 	if host != "https://gitlab.com" {
 		glOpts = append(glOpts, gitlab.WithBaseURL(host))
 	}
 
-	client, err = gitlab.NewClient(opts.Token, glOpts...)
+	client, err := gitlab.NewClient(opts.Token, glOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("could not create GitLab client: %v", err)
+		return nil, nil, fmt.Errorf("could not create GitLab client: %v", err)
 	}
 
-	return &GitLabProvider{
-		Client:  client,
-		Project: project,
-	}, nil
+	return client, transport, nil
+}
+
+// RepositoryPath returns the project path including the group and subgroups.
+func (p *GitLabProvider) RepositoryPath() string {
+	return p.Project
+}
+
+// RateLimit returns the GitLab API rate limit status from the last response.
+func (p *GitLabProvider) RateLimit() *RateLimit {
+	if p.transport == nil {
+		return nil
+	}
+	return p.transport.RateLimit()
 }
 
 func (p *GitLabProvider) ListBranches(ctx context.Context, opts Options) ([]Result, error) {
@@ -82,10 +151,6 @@ func (p *GitLabProvider) ListBranches(ctx context.Context, opts Options) ([]Resu
 				SHA:    branch.Commit.ID,
 				Branch: branch.Name,
 			})
-
-			if opts.Filters.Limit > 0 && len(results) >= opts.Filters.Limit {
-				return results, nil
-			}
 		}
 
 		if resp.NextPage == 0 {
@@ -94,7 +159,7 @@ func (p *GitLabProvider) ListBranches(ctx context.Context, opts Options) ([]Resu
 		glOpts.Page = resp.NextPage
 	}
 
-	return results, nil
+	return p.limit(opts, results), nil
 }
 
 func (p *GitLabProvider) ListRequests(ctx context.Context, opts Options) ([]Result, error) {
@@ -113,6 +178,7 @@ func (p *GitLabProvider) ListRequests(ctx context.Context, opts Options) ([]Resu
 	}
 
 	var results []Result
+	sourceProjects := make(map[string]int)
 	for {
 		msrs, resp, err := p.Client.MergeRequests.ListProjectMergeRequests(p.Project, glOpts)
 		if err != nil {
@@ -124,17 +190,207 @@ func (p *GitLabProvider) ListRequests(ctx context.Context, opts Options) ([]Resu
 				continue
 			}
 
+			req := requestInfo{
+				BaseBranch: mr.TargetBranch,
+				Draft:      mr.Draft,
+				Fork:       mr.SourceProjectID != mr.TargetProjectID,
+			}
+			if mr.Author != nil {
+				req.Author = mr.Author.Username
+			}
+			if mr.CreatedAt != nil {
+				req.CreatedAt = *mr.CreatedAt
+			}
+			if !matchRequest(opts, req) {
+				continue
+			}
+
+			result := Result{
+				ID:             fmt.Sprintf("%d", mr.IID),
+				SHA:            mr.SHA,
+				Branch:         mr.SourceBranch,
+				Title:          mr.Title,
+				Author:         mr.Author.Username,
+				Labels:         mr.Labels,
+				Number:         mr.IID,
+				URL:            mr.WebURL,
+				BaseBranch:     mr.TargetBranch,
+				HeadRepository: p.Project,
+				Draft:          mr.Draft,
+			}
+			if mr.CreatedAt != nil {
+				result.CreatedAt = formatTime(*mr.CreatedAt)
+			}
+			if mr.UpdatedAt != nil {
+				result.UpdatedAt = formatTime(*mr.UpdatedAt)
+			}
+			if req.Fork {
+				sourceProjects[result.ID] = mr.SourceProjectID
+			}
+			results = append(results, result)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		glOpts.Page = resp.NextPage
+	}
+
+	results = p.limit(opts, results)
+
+	// Resolve the path of the forked projects, once per project.
+	// The head repository is left empty if the project can't be fetched,
+	// unless the request is rate limited or forbidden.
+	forks := make(map[int]string)
+	for i := range results {
+		id, ok := sourceProjects[results[i].ID]
+		if !ok {
+			continue
+		}
+		if _, ok := forks[id]; !ok {
+			project, resp, err := p.Client.Projects.GetProject(id, nil, gitlab.WithContext(ctx))
+			if err != nil {
+				if isFatalLookupError(ctx, gitlabResponse(resp)) {
+					return nil, fmt.Errorf("could not get project %d: %w", id, err)
+				}
+				logr.FromContextOrDiscard(ctx).Error(err, "failed to get the merge request source project",
+					"number", results[i].Number, "project", id)
+				forks[id] = ""
+			} else {
+				forks[id] = project.PathWithNamespace
+			}
+		}
+		results[i].HeadRepository = forks[id]
+	}
+
+	// Fetch the commit timestamp of the merge requests head when enabled,
+	// the commits of the forks are available in the target project.
+	// The timestamp is left empty if the commit can't be fetched,
+	// unless the request is rate limited or forbidden.
+	if opts.CommitTimestamp {
+		for i := range results {
+			commit, resp, err := p.Client.Commits.GetCommit(p.Project, results[i].SHA, nil, gitlab.WithContext(ctx))
+			if err != nil {
+				if isFatalLookupError(ctx, gitlabResponse(resp)) {
+					return nil, fmt.Errorf("could not get commit %s: %w", results[i].SHA, err)
+				}
+				logr.FromContextOrDiscard(ctx).Error(err, "failed to get the merge request head commit",
+					"number", results[i].Number, "sha", results[i].SHA)
+				continue
+			}
+			if commit.CommittedDate == nil {
+				continue
+			}
+			results[i].CommitTimestamp = formatTime(*commit.CommittedDate)
+		}
+	}
+
+	return results, nil
+}
+
+func (p *GitLabProvider) ListTags(ctx context.Context, opts Options) ([]Result, error) {
+	glOpts := &gitlab.ListTagsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	var results []Result
+	for {
+		tags, resp, err := p.Client.Tags.ListTags(p.Project, glOpts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list tags: %v", err)
+		}
+
+		for _, tag := range tags {
+			var sha string
+			if tag.Commit != nil {
+				sha = tag.Commit.ID
+			}
+
 			results = append(results, Result{
-				ID:     fmt.Sprintf("%d", mr.IID),
-				SHA:    mr.SHA,
-				Branch: mr.SourceBranch,
-				Title:  mr.Title,
-				Author: mr.Author.Username,
-				Labels: mr.Labels,
+				ID:  checksum(tag.Name),
+				SHA: sha,
+				Tag: tag.Name,
 			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		glOpts.Page = resp.NextPage
+	}
+
+	return p.limit(opts, filterTags(opts, results, false)), nil
+}
+
+// ReportStatus sets the pipeline status of the commit with the given SHA, unless
+// the latest status with the same name has the same state and description.
+func (p *GitLabProvider) ReportStatus(ctx context.Context, sha string, status CommitStatus) error {
+	statuses, _, err := p.Client.Commits.GetCommitStatuses(p.Project, sha, &gitlab.GetCommitStatusesOptions{
+		Name: gitlab.Ptr(status.Context),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("could not get commit status %s: %v", sha, err)
+	}
+
+	state := gitlab.Pending
+	switch status.State {
+	case CommitStatusSuccess:
+		state = gitlab.Success
+	case CommitStatusFailure:
+		state = gitlab.Failed
+	}
 
-			if opts.Filters.Limit > 0 && len(results) >= opts.Filters.Limit {
-				return results, nil
+	var latest *gitlab.CommitStatus
+	for _, s := range statuses {
+		if s.Name == status.Context && (latest == nil || s.ID > latest.ID) {
+			latest = s
+		}
+	}
+	if latest != nil && latest.Status == string(state) && latest.Description == status.description() {
+		return nil
+	}
+
+	glOpts := &gitlab.SetCommitStatusOptions{
+		State:       state,
+		Name:        gitlab.Ptr(status.Context),
+		Description: gitlab.Ptr(status.description()),
+	}
+	if status.TargetURL != "" {
+		glOpts.TargetURL = gitlab.Ptr(status.TargetURL)
+	}
+
+	if _, _, err := p.Client.Commits.SetCommitStatus(p.Project, sha, glOpts, gitlab.WithContext(ctx)); err != nil {
+		return fmt.Errorf("could not set commit status %s: %v", sha, err)
+	}
+
+	return nil
+}
+
+// LabeledBy returns the user that last added the label
+// to the merge request, based on the resource label events.
+func (p *GitLabProvider) LabeledBy(ctx context.Context, number int, label string) (string, time.Time, error) {
+	glOpts := &gitlab.ListLabelEventsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	var user string
+	var at time.Time
+	for {
+		events, resp, err := p.Client.ResourceLabelEvents.ListMergeRequestsLabelEvents(p.Project, number, glOpts, gitlab.WithContext(ctx))
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("could not list label events of merge request %d: %v", number, err)
+		}
+
+		for _, event := range events {
+			if event.Action == "add" && event.Label.Name == label {
+				user = event.User.Username
+				if event.CreatedAt != nil {
+					at = *event.CreatedAt
+				}
 			}
 		}
 
@@ -144,17 +400,132 @@ func (p *GitLabProvider) ListRequests(ctx context.Context, opts Options) ([]Resu
 		glOpts.Page = resp.NextPage
 	}
 
-	return results, nil
+	return user, at, nil
+}
+
+// ListApprovers returns the users that approved the merge request. GitLab
+// doesn't record the approved commit, the SHA is ignored and the review
+// approvals can't be used together with reapproveOnNewCommit.
+func (p *GitLabProvider) ListApprovers(ctx context.Context, number int, _ string) ([]string, error) {
+	approvals, _, err := p.Client.MergeRequestApprovals.GetConfiguration(p.Project, number, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("could not get approvals of merge request %d: %v", number, err)
+	}
+
+	var approvers []string
+	for _, approver := range approvals.ApprovedBy {
+		if approver.User != nil {
+			approvers = append(approvers, approver.User.Username)
+		}
+	}
+	slices.Sort(approvers)
+
+	return approvers, nil
+}
+
+// ReadFile returns the content of the file at the given
+// path and the SHA of the commit the ref points to.
+func (p *GitLabProvider) ReadFile(ctx context.Context, path, ref string) ([]byte, string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	file, _, err := p.Client.RepositoryFiles.GetFile(p.Project, path, &gitlab.GetFileOptions{
+		Ref: gitlab.Ptr(ref),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, "", fmt.Errorf("could not get file %s: %v", path, err)
+	}
+
+	content := []byte(file.Content)
+	if file.Encoding == "base64" {
+		content, err = base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not decode file %s: %v", path, err)
+		}
+	}
+
+	return content, file.CommitID, nil
+}
+
+// ListRepositories returns the projects of the group sorted by path,
+// excluding the archived projects by default. The projects of the
+// subgroups are included only if the subgroups filter is set.
+func (p *GitLabProvider) ListRepositories(ctx context.Context, opts Options) ([]Result, error) {
+	glOpts := &gitlab.ListGroupProjectsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+		},
+		IncludeSubGroups: gitlab.Ptr(opts.Filters.IncludeSubgroups),
+		OrderBy:          gitlab.Ptr("path"),
+		Sort:             gitlab.Ptr("asc"),
+	}
+	if !opts.Filters.IncludeArchived {
+		glOpts.Archived = gitlab.Ptr(false)
+	}
+	if opts.Filters.Visibility != "" {
+		glOpts.Visibility = gitlab.Ptr(gitlab.VisibilityValue(strings.ToLower(opts.Filters.Visibility)))
+	}
+
+	var results []Result
+	for {
+		projects, resp, err := p.Client.Groups.ListGroupProjects(p.Group, glOpts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("could not list projects: %v", err)
+		}
+
+		for _, project := range projects {
+			if !matchRepository(opts, repositoryInfo{
+				Topics:     project.Topics,
+				Visibility: string(project.Visibility),
+				Archived:   project.Archived,
+			}) {
+				continue
+			}
+
+			result := Result{
+				ID:             checksum(project.PathWithNamespace),
+				Repository:     project.Path,
+				RepositoryPath: project.PathWithNamespace,
+				URL:            project.WebURL,
+				CloneURL:       project.HTTPURLToRepo,
+				DefaultBranch:  project.DefaultBranch,
+				Topics:         project.Topics,
+			}
+			if project.CreatedAt != nil {
+				result.CreatedAt = formatTime(*project.CreatedAt)
+			}
+			if project.LastActivityAt != nil {
+				result.UpdatedAt = formatTime(*project.LastActivityAt)
+			}
+			results = append(results, result)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		glOpts.Page = resp.NextPage
+	}
+
+	return p.limit(opts, results), nil
+}
+
+// gitlabResponse returns the HTTP response of the API call, if any.
+func gitlabResponse(resp *gitlab.Response) *http.Response {
+	if resp == nil {
+		return nil
+	}
+	return resp.Response
 }
 
-// parseGitHubURL parses a GitLab URL and returns the host and project.
+// parseGitLabURL parses a GitLab URL and returns the host and project.
 func parseGitLabURL(glURL string) (string, string, error) {
 	u, err := url.Parse(glURL)
 	if err != nil {
 		return "", "", fmt.Errorf("invalid URL %q: %w", glURL, err)
 	}
 
-	project := strings.TrimLeft(u.Path, "/")
+	project := strings.Trim(u.Path, "/")
 	if len(project) < 1 {
 		return "", "", fmt.Errorf("invalid GitLab URL %q: can't find project", glURL)
 	}
@@ -5,9 +5,13 @@ package gitprovider
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 )
@@ -110,44 +114,59 @@ func TestGitLabProvider_ListRequests(t *testing.T) {
 			},
 			want: []Result{
 				{
-					ID:     "5",
-					SHA:    "3fd0d45b23e5f14089587a9049e33d82497b944b",
-					Author: "stefanprodan",
-					Title:  "test5: Edit README.md",
-					Branch: "feat/5",
-					Labels: []string{},
+					ID:             "5",
+					SHA:            "3fd0d45b23e5f14089587a9049e33d82497b944b",
+					Author:         "stefanprodan",
+					Title:          "test5: Edit README.md",
+					Branch:         "feat/5",
+					Labels:         []string{},
+					Number:         5,
+					URL:            "https://gitlab.com/stefanprodan/podinfo/-/merge_requests/5",
+					HeadRepository: "stefanprodan/podinfo",
 				},
 				{
-					ID:     "4",
-					SHA:    "a143f78b7f8abd511a4f4ce84b4875edfb621a56",
-					Author: "stefanprodan",
-					Title:  "test4: Edit README.md",
-					Branch: "patch-4",
-					Labels: []string{"documentation", "enhancement"},
+					ID:             "4",
+					SHA:            "a143f78b7f8abd511a4f4ce84b4875edfb621a56",
+					Author:         "stefanprodan",
+					Title:          "test4: Edit README.md",
+					Branch:         "patch-4",
+					Labels:         []string{"documentation", "enhancement"},
+					Number:         4,
+					URL:            "https://gitlab.com/stefanprodan/podinfo/-/merge_requests/4",
+					HeadRepository: "stefanprodan/podinfo",
 				},
 				{
-					ID:     "3",
-					SHA:    "f2aed00334494f13d92d065ecda39aea0d0b871f",
-					Author: "stefanprodan",
-					Title:  "test3: Edit README.md",
-					Branch: "patch-3",
-					Labels: []string{"documentation"},
+					ID:             "3",
+					SHA:            "f2aed00334494f13d92d065ecda39aea0d0b871f",
+					Author:         "stefanprodan",
+					Title:          "test3: Edit README.md",
+					Branch:         "patch-3",
+					Labels:         []string{"documentation"},
+					Number:         3,
+					URL:            "https://gitlab.com/stefanprodan/podinfo/-/merge_requests/3",
+					HeadRepository: "stefanprodan/podinfo",
 				},
 				{
-					ID:     "2",
-					SHA:    "a275fb0322466eaa1a74485a4f79f88d7c8858e8",
-					Author: "stefanprodan",
-					Title:  "test2: Edit README.md",
-					Branch: "patch-2",
-					Labels: []string{"enhancement"},
+					ID:             "2",
+					SHA:            "a275fb0322466eaa1a74485a4f79f88d7c8858e8",
+					Author:         "stefanprodan",
+					Title:          "test2: Edit README.md",
+					Branch:         "patch-2",
+					Labels:         []string{"enhancement"},
+					Number:         2,
+					URL:            "https://gitlab.com/stefanprodan/podinfo/-/merge_requests/2",
+					HeadRepository: "stefanprodan/podinfo",
 				},
 				{
-					ID:     "1",
-					SHA:    "cebef2d870bc83b37f43c470bae205fca094bacc",
-					Author: "stefanprodan",
-					Title:  "test1: Edit README.md",
-					Branch: "patch-1",
-					Labels: []string{"enhancement"},
+					ID:             "1",
+					SHA:            "cebef2d870bc83b37f43c470bae205fca094bacc",
+					Author:         "stefanprodan",
+					Title:          "test1: Edit README.md",
+					Branch:         "patch-1",
+					Labels:         []string{"enhancement"},
+					Number:         1,
+					URL:            "https://gitlab.com/stefanprodan/podinfo/-/merge_requests/1",
+					HeadRepository: "stefanprodan/podinfo",
 				},
 			},
 		},
@@ -163,20 +182,26 @@ func TestGitLabProvider_ListRequests(t *testing.T) {
 			},
 			want: []Result{
 				{
-					ID:     "4",
-					SHA:    "a143f78b7f8abd511a4f4ce84b4875edfb621a56",
-					Author: "stefanprodan",
-					Title:  "test4: Edit README.md",
-					Branch: "patch-4",
-					Labels: []string{"documentation", "enhancement"},
+					ID:             "4",
+					SHA:            "a143f78b7f8abd511a4f4ce84b4875edfb621a56",
+					Author:         "stefanprodan",
+					Title:          "test4: Edit README.md",
+					Branch:         "patch-4",
+					Labels:         []string{"documentation", "enhancement"},
+					Number:         4,
+					URL:            "https://gitlab.com/stefanprodan/podinfo/-/merge_requests/4",
+					HeadRepository: "stefanprodan/podinfo",
 				},
 				{
-					ID:     "2",
-					SHA:    "a275fb0322466eaa1a74485a4f79f88d7c8858e8",
-					Author: "stefanprodan",
-					Title:  "test2: Edit README.md",
-					Branch: "patch-2",
-					Labels: []string{"enhancement"},
+					ID:             "2",
+					SHA:            "a275fb0322466eaa1a74485a4f79f88d7c8858e8",
+					Author:         "stefanprodan",
+					Title:          "test2: Edit README.md",
+					Branch:         "patch-2",
+					Labels:         []string{"enhancement"},
+					Number:         2,
+					URL:            "https://gitlab.com/stefanprodan/podinfo/-/merge_requests/2",
+					HeadRepository: "stefanprodan/podinfo",
 				},
 			},
 		},
@@ -191,12 +216,15 @@ func TestGitLabProvider_ListRequests(t *testing.T) {
 			},
 			want: []Result{
 				{
-					ID:     "5",
-					SHA:    "3fd0d45b23e5f14089587a9049e33d82497b944b",
-					Author: "stefanprodan",
-					Title:  "test5: Edit README.md",
-					Branch: "feat/5",
-					Labels: []string{},
+					ID:             "5",
+					SHA:            "3fd0d45b23e5f14089587a9049e33d82497b944b",
+					Author:         "stefanprodan",
+					Title:          "test5: Edit README.md",
+					Branch:         "feat/5",
+					Labels:         []string{},
+					Number:         5,
+					URL:            "https://gitlab.com/stefanprodan/podinfo/-/merge_requests/5",
+					HeadRepository: "stefanprodan/podinfo",
 				},
 			},
 		},
@@ -232,7 +260,280 @@ func TestGitLabProvider_ListRequests(t *testing.T) {
 			}
 			g.Expect(err).NotTo(HaveOccurred())
 
-			g.Expect(got).To(BeEquivalentTo(tt.want))
+			g.Expect(stripRequestTimes(g, got)).To(BeEquivalentTo(tt.want))
 		})
 	}
 }
+
+func TestGitLabProvider_ListRepositories(t *testing.T) {
+	tests := []struct {
+		name      string
+		filters   Filters
+		wantQuery map[string]string
+		want      []string
+	}{
+		{
+			name: "excludes archived by default",
+			wantQuery: map[string]string{
+				"archived":          "false",
+				"include_subgroups": "false",
+				"order_by":          "path",
+			},
+			want: []string{"app1", "app2"},
+		},
+		{
+			name:    "includes subgroups and archived",
+			filters: Filters{IncludeSubgroups: true, IncludeArchived: true},
+			wantQuery: map[string]string{
+				"archived":          "",
+				"include_subgroups": "true",
+			},
+			want: []string{"app1", "app2"},
+		},
+		{
+			name:      "matches topics and visibility",
+			filters:   Filters{Topics: []string{"flux"}, Visibility: "Private"},
+			wantQuery: map[string]string{"visibility": "private"},
+			want:      []string{"app2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v4/groups/fluxcd-testing/projects", func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.wantQuery {
+					g.Expect(r.URL.Query().Get(k)).To(Equal(v), "query parameter %s", k)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[
+{"path":"app1","path_with_namespace":"fluxcd-testing/app1","web_url":"https://gitlab.example.com/fluxcd-testing/app1",
+ "http_url_to_repo":"https://gitlab.example.com/fluxcd-testing/app1.git","default_branch":"main",
+ "topics":["app"],"visibility":"public","created_at":"2024-01-01T00:00:00Z","last_activity_at":"2025-01-01T00:00:00Z"},
+{"path":"app2","path_with_namespace":"fluxcd-testing/team/app2","default_branch":"main",
+ "topics":["app","flux"],"visibility":"private"}]`))
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			provider, err := NewGitLabGroupProvider(context.Background(), Options{
+				URL: srv.URL + "/fluxcd-testing",
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			results, err := provider.ListRepositories(context.Background(), Options{Filters: tt.filters})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			for _, r := range results {
+				names = append(names, r.Repository)
+			}
+			g.Expect(names).To(Equal(tt.want))
+
+			if names[0] != "app1" {
+				return
+			}
+
+			// The first project exports all the fields.
+			g.Expect(results[0]).To(Equal(Result{
+				ID:             checksum("fluxcd-testing/app1"),
+				Repository:     "app1",
+				RepositoryPath: "fluxcd-testing/app1",
+				URL:            "https://gitlab.example.com/fluxcd-testing/app1",
+				CloneURL:       "https://gitlab.example.com/fluxcd-testing/app1.git",
+				DefaultBranch:  "main",
+				Topics:         []string{"app"},
+				CreatedAt:      "2024-01-01T00:00:00Z",
+				UpdatedAt:      "2025-01-01T00:00:00Z",
+			}))
+		})
+	}
+}
+
+func TestGitLabProvider_ReadFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		ref        string
+		wantRef    string
+		wantErrMsg string
+	}{
+		{
+			name:    "reads from the default branch",
+			wantRef: "HEAD",
+		},
+		{
+			name:    "reads from ref",
+			ref:     "v1.0.0",
+			wantRef: "v1.0.0",
+		},
+		{
+			name:       "fails for unknown ref",
+			ref:        "unknown",
+			wantErrMsg: "could not get file envs/inputs.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v4/projects/{project}/repository/files/{file}", func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.PathValue("project")).To(Equal("fluxcd-testing/app"))
+				g.Expect(r.PathValue("file")).To(Equal("envs/inputs.yaml"))
+				if r.URL.Query().Get("ref") != tt.wantRef {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message":"404 Commit Not Found"}`))
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"file_path":"envs/inputs.yaml","encoding":"base64",
+ "content":"LSBlbnY6IHN0YWdpbmcK","commit_id":"a1b2c3d4"}`))
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			provider, err := NewGitLabProvider(context.Background(), Options{
+				URL: srv.URL + "/fluxcd-testing/app",
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			content, revision, err := provider.ReadFile(context.Background(), "envs/inputs.yaml", tt.ref)
+			if tt.wantErrMsg != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(content)).To(Equal("- env: staging\n"))
+			g.Expect(revision).To(Equal("a1b2c3d4"))
+		})
+	}
+}
+
+func TestGitLabProvider_ReportStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     CommitStatus
+		wantCreate map[string]any
+	}{
+		{
+			name: "skips unchanged latest status",
+			status: CommitStatus{
+				Context:     "flux-operator/apps/preview",
+				State:       CommitStatusFailure,
+				Description: "reconciliation failed",
+			},
+		},
+		{
+			name: "creates status matching an older status",
+			status: CommitStatus{
+				Context:     "flux-operator/apps/preview",
+				State:       CommitStatusSuccess,
+				Description: "Reconciliation succeeded",
+			},
+			wantCreate: map[string]any{
+				"name":        "flux-operator/apps/preview",
+				"state":       "success",
+				"description": "Reconciliation succeeded",
+			},
+		},
+		{
+			name: "creates pending status",
+			status: CommitStatus{
+				Context:     "flux-operator/apps/preview",
+				State:       CommitStatusPending,
+				Description: "dependency not ready",
+				TargetURL:   "https://flux.example.com",
+			},
+			wantCreate: map[string]any{
+				"name":        "flux-operator/apps/preview",
+				"state":       "pending",
+				"description": "dependency not ready",
+				"target_url":  "https://flux.example.com",
+			},
+		},
+		{
+			name: "creates failed status for new description",
+			status: CommitStatus{
+				Context:     "flux-operator/apps/preview",
+				State:       CommitStatusFailure,
+				Description: "health check failed",
+			},
+			wantCreate: map[string]any{
+				"name":        "flux-operator/apps/preview",
+				"state":       "failed",
+				"description": "health check failed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var created map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v4/projects/{project}/repository/commits/a1/statuses", func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.URL.Query().Get("name")).To(Equal(tt.status.Context))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[
+{"id":2,"name":"flux-operator/apps/preview","status":"failed","description":"reconciliation failed"},
+{"id":1,"name":"flux-operator/apps/preview","status":"success","description":"Reconciliation succeeded"}]`))
+			})
+			mux.HandleFunc("POST /api/v4/projects/{project}/statuses/a1", func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(json.NewDecoder(r.Body).Decode(&created)).To(Succeed())
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{}`))
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			provider, err := NewGitLabProvider(context.Background(), Options{
+				URL: srv.URL + "/fluxcd-testing/app",
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			err = provider.ReportStatus(context.Background(), "a1", tt.status)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(created).To(Equal(tt.wantCreate))
+		})
+	}
+}
+
+func TestGitLabProvider_Approvals(t *testing.T) {
+	g := NewWithT(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/merge_requests/4/resource_label_events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+{"action":"add","user":{"username":"mallory"},"label":{"name":"deploy"},"created_at":"2025-04-01T10:00:00Z"},
+{"action":"remove","user":{"username":"alice"},"label":{"name":"deploy"},"created_at":"2025-04-01T11:00:00Z"},
+{"action":"add","user":{"username":"alice"},"label":{"name":"deploy"},"created_at":"2025-04-02T10:00:00Z"}]`))
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}/merge_requests/4/approvals", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"iid":4,"approved_by":[{"user":{"username":"carol"}},{"user":{"username":"alice"}}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	provider, err := NewGitLabProvider(context.Background(), Options{
+		URL: srv.URL + "/fluxcd-testing/app",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	user, at, err := provider.LabeledBy(context.Background(), 4, "deploy")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(user).To(Equal("alice"))
+	g.Expect(at).To(BeTemporally("==", time.Date(2025, 4, 2, 10, 0, 0, 0, time.UTC)))
+
+	approvers, err := provider.ListApprovers(context.Background(), 4, "a1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(approvers).To(Equal([]string{"alice", "carol"}))
+}
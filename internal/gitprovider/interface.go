@@ -5,6 +5,7 @@ package gitprovider
 
 import (
 	"context"
+	"time"
 )
 
 // Interface that all Git SaaS providers must implement.
@@ -14,4 +15,70 @@ type Interface interface {
 
 	// ListRequests returns a list of pull/merge requests that match the filters.
 	ListRequests(ctx context.Context, opts Options) ([]Result, error)
+
+	// ListTags returns a list of semver tags that match the filters.
+	ListTags(ctx context.Context, opts Options) ([]Result, error)
+}
+
+// RepositoryLister is implemented by the providers that
+// can list the repositories of an organization or group.
+type RepositoryLister interface {
+	// ListRepositories returns a list of repositories that match the filters.
+	ListRepositories(ctx context.Context, opts Options) ([]Result, error)
+}
+
+// FileReader is implemented by the providers that
+// can read the content of a file from the repository.
+type FileReader interface {
+	// ReadFile returns the content of the file at the given path and the
+	// SHA of the commit it was read from. When the ref is empty, the file
+	// is read from the default branch of the repository.
+	ReadFile(ctx context.Context, path, ref string) ([]byte, string, error)
+}
+
+// ApprovalReader is implemented by the providers that can
+// report who approved or labeled a pull/merge request.
+type ApprovalReader interface {
+	// LabeledBy returns the username that last applied the label to the
+	// pull/merge request and the time when it was applied. The username
+	// is empty if the label was never applied.
+	LabeledBy(ctx context.Context, number int, label string) (string, time.Time, error)
+
+	// ListApprovers returns the usernames that approved the pull/merge request.
+	// When the SHA is set, the providers that record the reviewed commit
+	// return only the approvals of that commit.
+	ListApprovers(ctx context.Context, number int, sha string) ([]string, error)
+}
+
+// LimitReporter is implemented by the providers that
+// report the number of results dropped by the limit filter.
+type LimitReporter interface {
+	// Dropped returns the number of results that matched the filters
+	// but were dropped by the limit in the last list call.
+	Dropped() int
+}
+
+// RepositoryIdentifier is implemented by the providers
+// that query a single repository.
+type RepositoryIdentifier interface {
+	// RepositoryPath returns the full path of the repository,
+	// e.g. 'owner/repo' or 'group/subgroup/project'.
+	RepositoryPath() string
+}
+
+// RateLimitReporter is implemented by the providers that
+// track the API rate limit status of the Git provider.
+type RateLimitReporter interface {
+	// RateLimit returns the rate limit status from the last API response,
+	// or nil if the Git provider didn't report the rate limit.
+	RateLimit() *RateLimit
+}
+
+// StatusReporter is implemented by the providers that
+// can report commit statuses to the Git provider.
+type StatusReporter interface {
+	// ReportStatus sets the status of the commit with the given SHA.
+	// The status is not reported if the latest status of the commit
+	// for the same context has the same state and description.
+	ReportStatus(ctx context.Context, sha string, status CommitStatus) error
 }
@@ -0,0 +1,97 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// OCIProvider implements the Interface for OCI artifact repositories.
+// Only listing tags is supported.
+type OCIProvider struct {
+	Repository string
+	Options    []crane.Option
+
+	resultLimiter
+}
+
+func NewOCIProvider(ctx context.Context, opts Options) (*OCIProvider, error) {
+	if !strings.HasPrefix(opts.URL, "oci://") {
+		return nil, fmt.Errorf("invalid OCI URL %q: must start with 'oci://'", opts.URL)
+	}
+
+	repo, err := name.NewRepository(strings.TrimPrefix(opts.URL, "oci://"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI URL %q: %w", opts.URL, err)
+	}
+
+	craneOpts := []crane.Option{
+		crane.WithAuthFromKeychain(opts.Keychain),
+	}
+
+	if opts.CertPool != nil {
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = &tls.Config{
+			RootCAs: opts.CertPool,
+		}
+		craneOpts = append(craneOpts, crane.WithTransport(tr))
+	}
+
+	return &OCIProvider{
+		Repository: repo.String(),
+		Options:    craneOpts,
+	}, nil
+}
+
+// RepositoryPath returns the repository address without the 'oci://' prefix.
+func (p *OCIProvider) RepositoryPath() string {
+	return p.Repository
+}
+
+// ListBranches is not supported for OCI repositories.
+func (p *OCIProvider) ListBranches(ctx context.Context, opts Options) ([]Result, error) {
+	return nil, fmt.Errorf("listing branches is not supported for OCI repositories")
+}
+
+// ListRequests is not supported for OCI repositories.
+func (p *OCIProvider) ListRequests(ctx context.Context, opts Options) ([]Result, error) {
+	return nil, fmt.Errorf("listing requests is not supported for OCI repositories")
+}
+
+// ListTags returns the tags of the OCI repository that match the filters.
+// The digest of each tag is resolved after the filters are applied.
+func (p *OCIProvider) ListTags(ctx context.Context, opts Options) ([]Result, error) {
+	craneOpts := append([]crane.Option{crane.WithContext(ctx)}, p.Options...)
+
+	tags, err := crane.ListTags(p.Repository, craneOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not list tags: %v", err)
+	}
+
+	results := make([]Result, 0, len(tags))
+	for _, tag := range tags {
+		results = append(results, Result{
+			ID:  checksum(tag),
+			Tag: tag,
+		})
+	}
+
+	results = p.limit(opts, filterTags(opts, results, true))
+	for i := range results {
+		digest, err := crane.Digest(fmt.Sprintf("%s:%s", p.Repository, results[i].Tag), craneOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve digest for tag %s: %v", results[i].Tag, err)
+		}
+		results[i].Digest = digest
+	}
+
+	return results, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	. "github.com/onsi/gomega"
+)
+
+func newOCITestRegistry(t *testing.T, repo string, tags []string) (string, map[string]string) {
+	g := NewWithT(t)
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(srv.Close)
+
+	ref := fmt.Sprintf("%s/%s", strings.TrimPrefix(srv.URL, "http://"), repo)
+	digests := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		img, err := random.Image(32, 1)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(crane.Push(img, fmt.Sprintf("%s:%s", ref, tag))).To(Succeed())
+
+		digest, err := img.Digest()
+		g.Expect(err).NotTo(HaveOccurred())
+		digests[tag] = digest.String()
+	}
+
+	return "oci://" + ref, digests
+}
+
+func TestOCIProvider_ListTags(t *testing.T) {
+	ociURL, digests := newOCITestRegistry(t, "charts/app",
+		[]string{"1.0.0", "1.0.1", "1.1.0", "1.1.1", "2.0.0", "latest"})
+
+	tests := []struct {
+		name        string
+		semverRange string
+		filters     Filters
+		want        []string
+	}{
+		{
+			name:        "filters tags by semver range",
+			semverRange: "1.x",
+			filters: Filters{
+				LatestPerMinor: 1,
+			},
+			want: []string{"1.1.1", "1.0.1"},
+		},
+		{
+			name: "filters tags by regex",
+			filters: Filters{
+				IncludeTagRe: regexp.MustCompile(`^(latest|2\..*)$`),
+			},
+			want: []string{"2.0.0", "latest"},
+		},
+		{
+			name: "filters tags by limit",
+			filters: Filters{
+				Limit: 1,
+			},
+			want: []string{"2.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts := Options{
+				URL:     ociURL,
+				Filters: tt.filters,
+			}
+			if tt.semverRange != "" {
+				constraint, err := semver.NewConstraint(tt.semverRange)
+				g.Expect(err).NotTo(HaveOccurred())
+				opts.Filters.SemverRange = constraint
+			}
+
+			provider, err := NewOCIProvider(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := provider.ListTags(context.Background(), opts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			gotTags := make([]string, 0, len(got))
+			for _, r := range got {
+				g.Expect(r.ID).To(Equal(checksum(r.Tag)))
+				g.Expect(r.Digest).To(Equal(digests[r.Tag]))
+				gotTags = append(gotTags, r.Tag)
+			}
+			g.Expect(gotTags).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNewOCIProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewOCIProvider(context.Background(), Options{URL: "https://ghcr.io/org/app"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("must start with 'oci://'"))
+
+	provider, err := NewOCIProvider(context.Background(), Options{URL: "oci://ghcr.io/org/app"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(provider.Repository).To(Equal("ghcr.io/org/app"))
+}
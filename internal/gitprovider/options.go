@@ -7,22 +7,66 @@ import (
 	"crypto/x509"
 	"regexp"
 	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-containerregistry/pkg/authn"
 )
 
 // Options holds the configuration for the Git SaaS provider.
 type Options struct {
 	URL      string
 	CertPool *x509.CertPool
+	Username string
 	Token    string
+	Keychain authn.Keychain
 	Filters  Filters
+
+	// CommitTimestamp enables the lookup of the pull/merge requests
+	// head commit timestamp, which requires an API call per result.
+	CommitTimestamp bool
 }
 
 // Filters holds the filters for the Git SaaS responses.
 type Filters struct {
-	IncludeBranchRe *regexp.Regexp
-	ExcludeBranchRe *regexp.Regexp
-	Labels          []string
-	Limit           int
+	IncludeBranchRe  *regexp.Regexp
+	ExcludeBranchRe  *regexp.Regexp
+	IncludeTagRe     *regexp.Regexp
+	ExcludeTagRe     *regexp.Regexp
+	Labels           []string
+	SemverRange      *semver.Constraints
+	LatestPerMinor   int
+	Limit            int
+	BaseBranchRe     *regexp.Regexp
+	ExcludeDraft     bool
+	ExcludeForks     bool
+	IncludeAuthors   []string
+	ExcludeAuthors   []string
+	MaxAge           time.Duration
+	Topics           []string
+	Visibility       string
+	IncludeArchived  bool
+	IncludeSubgroups bool
+	SortBy           string
+	SortDescending   bool
+	TitleLabelPrefix string
+}
+
+// requestInfo holds the pull/merge request attributes used by the request filters.
+type requestInfo struct {
+	BaseBranch string
+	Author     string
+	Draft      bool
+	Fork       bool
+	CreatedAt  time.Time
+}
+
+// repositoryInfo holds the repository attributes used by the repository filters.
+type repositoryInfo struct {
+	Topics     []string
+	Visibility string
+	Archived   bool
 }
 
 // matchBranch returns true if the branch matches the include and exclude regex filters.
@@ -41,6 +85,22 @@ func matchBranch(opt Options, branch string) bool {
 	return true
 }
 
+// matchTag returns true if the tag matches the include and exclude regex filters.
+func matchTag(opt Options, tag string) bool {
+	if opt.Filters.IncludeTagRe != nil {
+		if !opt.Filters.IncludeTagRe.MatchString(tag) {
+			return false
+		}
+	}
+
+	if opt.Filters.ExcludeTagRe != nil {
+		if opt.Filters.ExcludeTagRe.MatchString(tag) {
+			return false
+		}
+	}
+	return true
+}
+
 // matchLabels returns true if the given labels include all the label filters.
 func matchLabels(opt Options, labels []string) bool {
 	for _, label := range opt.Filters.Labels {
@@ -50,3 +110,56 @@ func matchLabels(opt Options, labels []string) bool {
 	}
 	return true
 }
+
+// matchRequest returns true if the pull/merge request matches the base branch,
+// draft, fork, author and max age filters. The authors are matched case-insensitive.
+func matchRequest(opt Options, req requestInfo) bool {
+	if opt.Filters.BaseBranchRe != nil && !opt.Filters.BaseBranchRe.MatchString(req.BaseBranch) {
+		return false
+	}
+
+	if opt.Filters.ExcludeDraft && req.Draft {
+		return false
+	}
+
+	if opt.Filters.ExcludeForks && req.Fork {
+		return false
+	}
+
+	matchAuthor := func(author string) bool {
+		return strings.EqualFold(author, req.Author)
+	}
+	if len(opt.Filters.IncludeAuthors) > 0 && !slices.ContainsFunc(opt.Filters.IncludeAuthors, matchAuthor) {
+		return false
+	}
+	if slices.ContainsFunc(opt.Filters.ExcludeAuthors, matchAuthor) {
+		return false
+	}
+
+	if opt.Filters.MaxAge > 0 && !req.CreatedAt.IsZero() && time.Since(req.CreatedAt) > opt.Filters.MaxAge {
+		return false
+	}
+
+	return true
+}
+
+// matchRepository returns true if the repository has all the topic filters,
+// matches the visibility filter and is not archived unless archived
+// repositories are included. The visibility is matched case-insensitive.
+func matchRepository(opt Options, repo repositoryInfo) bool {
+	for _, topic := range opt.Filters.Topics {
+		if !slices.Contains(repo.Topics, topic) {
+			return false
+		}
+	}
+
+	if opt.Filters.Visibility != "" && !strings.EqualFold(opt.Filters.Visibility, repo.Visibility) {
+		return false
+	}
+
+	if repo.Archived && !opt.Filters.IncludeArchived {
+		return false
+	}
+
+	return true
+}
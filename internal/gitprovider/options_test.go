@@ -0,0 +1,85 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMatchRequest(t *testing.T) {
+	req := requestInfo{
+		BaseBranch: "main",
+		Author:     "StefanProdan",
+		Draft:      true,
+		Fork:       true,
+		CreatedAt:  time.Now().Add(-48 * time.Hour),
+	}
+
+	tests := []struct {
+		name    string
+		filters Filters
+		want    bool
+	}{
+		{
+			name: "no filters",
+			want: true,
+		},
+		{
+			name:    "matches base branch",
+			filters: Filters{BaseBranchRe: regexp.MustCompile(`^(main|release/.*)$`)},
+			want:    true,
+		},
+		{
+			name:    "excludes base branch",
+			filters: Filters{BaseBranchRe: regexp.MustCompile(`^release/.*`)},
+			want:    false,
+		},
+		{
+			name:    "excludes draft",
+			filters: Filters{ExcludeDraft: true},
+			want:    false,
+		},
+		{
+			name:    "excludes fork",
+			filters: Filters{ExcludeForks: true},
+			want:    false,
+		},
+		{
+			name:    "includes author case-insensitive",
+			filters: Filters{IncludeAuthors: []string{"alice", "stefanprodan"}},
+			want:    true,
+		},
+		{
+			name:    "excludes author not in allow list",
+			filters: Filters{IncludeAuthors: []string{"alice"}},
+			want:    false,
+		},
+		{
+			name:    "excludes author in deny list",
+			filters: Filters{ExcludeAuthors: []string{"stefanprodan"}},
+			want:    false,
+		},
+		{
+			name:    "includes recent request",
+			filters: Filters{MaxAge: 72 * time.Hour},
+			want:    true,
+		},
+		{
+			name:    "excludes old request",
+			filters: Filters{MaxAge: 24 * time.Hour},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(matchRequest(Options{Filters: tt.filters}, req)).To(Equal(tt.want))
+		})
+	}
+}
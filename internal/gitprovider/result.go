@@ -6,6 +6,8 @@ package gitprovider
 import (
 	"fmt"
 	"hash/adler32"
+	"path"
+	"time"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -13,20 +15,55 @@ import (
 
 // Result holds the information extracted from the Git SaaS provider response.
 type Result struct {
-	ID     string   `json:"id"`
-	SHA    string   `json:"sha"`
-	Branch string   `json:"branch"`
-	Author string   `json:"author,omitempty"`
-	Title  string   `json:"title,omitempty"`
-	Labels []string `json:"labels,omitempty"`
+	ID              string   `json:"id"`
+	SHA             string   `json:"sha"`
+	Branch          string   `json:"branch,omitempty"`
+	Tag             string   `json:"tag,omitempty"`
+	Version         string   `json:"version,omitempty"`
+	Digest          string   `json:"digest,omitempty"`
+	Author          string   `json:"author,omitempty"`
+	Title           string   `json:"title,omitempty"`
+	Labels          []string `json:"labels,omitempty"`
+	Number          int      `json:"number,omitempty"`
+	URL             string   `json:"url,omitempty"`
+	BaseBranch      string   `json:"baseBranch,omitempty"`
+	HeadRepository  string   `json:"headRepository,omitempty"`
+	CommitTimestamp string   `json:"commitTimestamp,omitempty"`
+	Draft           bool     `json:"draft,omitempty"`
+	CreatedAt       string   `json:"createdAt,omitempty"`
+	UpdatedAt       string   `json:"updatedAt,omitempty"`
+	Repository      string   `json:"repository,omitempty"`
+	RepositoryPath  string   `json:"repositoryPath,omitempty"`
+	CloneURL        string   `json:"cloneURL,omitempty"`
+	DefaultBranch   string   `json:"defaultBranch,omitempty"`
+	Topics          []string `json:"topics,omitempty"`
 }
 
 // ToMap converts the result into a map.
 func (r *Result) ToMap() map[string]any {
 	m := map[string]any{
-		"id":     r.ID,
-		"sha":    r.SHA,
-		"branch": r.Branch,
+		"id": r.ID,
+	}
+
+	// The OCI artifact tags and the repositories have no commit SHA.
+	if r.SHA != "" {
+		m["sha"] = r.SHA
+	}
+
+	if r.Branch != "" {
+		m["branch"] = r.Branch
+	}
+
+	if r.Tag != "" {
+		m["tag"] = r.Tag
+	}
+
+	if r.Version != "" {
+		m["version"] = r.Version
+	}
+
+	if r.Digest != "" {
+		m["digest"] = r.Digest
 	}
 
 	if r.Author != "" {
@@ -41,6 +78,56 @@ func (r *Result) ToMap() map[string]any {
 		m["labels"] = r.Labels
 	}
 
+	// The draft flag is exported for all pull/merge requests.
+	if r.Number > 0 {
+		m["number"] = r.Number
+		m["draft"] = r.Draft
+	}
+
+	if r.URL != "" {
+		m["url"] = r.URL
+	}
+
+	if r.BaseBranch != "" {
+		m["baseBranch"] = r.BaseBranch
+	}
+
+	if r.HeadRepository != "" {
+		m["headRepository"] = r.HeadRepository
+	}
+
+	if r.CommitTimestamp != "" {
+		m["commitTimestamp"] = r.CommitTimestamp
+	}
+
+	if r.CreatedAt != "" {
+		m["createdAt"] = r.CreatedAt
+	}
+
+	if r.UpdatedAt != "" {
+		m["updatedAt"] = r.UpdatedAt
+	}
+
+	if r.Repository != "" {
+		m["repository"] = r.Repository
+	}
+
+	if r.RepositoryPath != "" {
+		m["repositoryPath"] = r.RepositoryPath
+	}
+
+	if r.CloneURL != "" {
+		m["cloneURL"] = r.CloneURL
+	}
+
+	if r.DefaultBranch != "" {
+		m["defaultBranch"] = r.DefaultBranch
+	}
+
+	if len(r.Topics) > 0 {
+		m["topics"] = r.Topics
+	}
+
 	return m
 }
 
@@ -72,6 +159,15 @@ func (r *Result) OverrideFromExportedInputs(input map[string]any) error {
 	return nil
 }
 
+// SetRepository sets the repository identity of the result and computes
+// the id from the repository path and the id returned by the provider,
+// so that the ids of the results merged from multiple repositories are unique.
+func (r *Result) SetRepository(repoPath string) {
+	r.Repository = path.Base(repoPath)
+	r.RepositoryPath = repoPath
+	r.ID = checksum(fmt.Sprintf("%s/%s", repoPath, r.ID))
+}
+
 // MakeInputs converts a list of results into a list of ResourceSet inputs with defaults.
 func MakeInputs(results []Result, defaults map[string]any) ([]map[string]*apiextensionsv1.JSON, error) {
 	inputs := make([]map[string]*apiextensionsv1.JSON, 0, len(results))
@@ -96,6 +192,15 @@ func MakeInputs(results []Result, defaults map[string]any) ([]map[string]*apiext
 	return inputs, nil
 }
 
+// formatTime returns the time in RFC3339 format,
+// or an empty string if the time is not set.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
 func checksum(txt string) string {
 	return fmt.Sprintf("%v", adler32.Checksum([]byte(txt)))
 }
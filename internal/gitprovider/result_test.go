@@ -5,6 +5,7 @@ package gitprovider
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/yaml"
@@ -110,6 +111,52 @@ func TestMakeInputs(t *testing.T) {
   numbers:
   - 1
   - 2
+`,
+		},
+		{
+			name: "pull request results with metadata",
+			results: []Result{
+				{
+					ID:              "5",
+					SHA:             "f43c54d06a19335cb8be4607ef9a05a3b20fb485",
+					Branch:          "feat/5",
+					Author:          "stefanprodan",
+					Title:           "test5: Update README.md",
+					Number:          5,
+					URL:             "https://github.com/fluxcd-testing/pr-testing/pull/5",
+					BaseBranch:      "main",
+					HeadRepository:  "fluxcd-testing/pr-testing",
+					CommitTimestamp: "2025-01-02T10:00:00Z",
+					CreatedAt:       "2025-01-01T10:00:00Z",
+					UpdatedAt:       "2025-01-03T10:00:00Z",
+				},
+				{
+					ID:     "4",
+					SHA:    "80332195632fe293564ff563344032cf4c75af45",
+					Branch: "patch-4",
+					Number: 4,
+					Draft:  true,
+				},
+			},
+			want: `
+- id: "5"
+  sha: "f43c54d06a19335cb8be4607ef9a05a3b20fb485"
+  branch: "feat/5"
+  author: "stefanprodan"
+  title: "test5: Update README.md"
+  number: 5
+  url: "https://github.com/fluxcd-testing/pr-testing/pull/5"
+  baseBranch: "main"
+  headRepository: "fluxcd-testing/pr-testing"
+  commitTimestamp: "2025-01-02T10:00:00Z"
+  draft: false
+  createdAt: "2025-01-01T10:00:00Z"
+  updatedAt: "2025-01-03T10:00:00Z"
+- id: "4"
+  sha: "80332195632fe293564ff563344032cf4c75af45"
+  branch: "patch-4"
+  number: 4
+  draft: true
 `,
 		},
 	}
@@ -160,6 +207,44 @@ func TestOverrideFromExportedInputs(t *testing.T) {
 				Labels: []string{"deploy"},
 			},
 		},
+		{
+			name: "results with pull request metadata",
+			result: Result{
+				ID:              "2",
+				SHA:             "6889f7524d5796de2570466f0bf50afdb78fb30e",
+				Branch:          "patch-2",
+				Labels:          []string{"deploy", "pipeline:pending"},
+				Number:          2,
+				URL:             "https://github.com/fluxcd-testing/pr-testing/pull/2",
+				BaseBranch:      "main",
+				HeadRepository:  "fluxcd-testing/pr-testing",
+				CommitTimestamp: "2025-01-03T10:00:00Z",
+				UpdatedAt:       "2025-01-03T10:00:00Z",
+			},
+			exportedInput: map[string]any{
+				"id":              "2",
+				"sha":             "2dd3a8d2088457e5cf991018edf13e25cbd61380",
+				"branch":          "patch-2",
+				"labels":          []string{"deploy"},
+				"number":          float64(2),
+				"draft":           true,
+				"commitTimestamp": "2025-01-02T10:00:00Z",
+				"updatedAt":       "2025-01-02T10:00:00Z",
+			},
+			want: Result{
+				ID:              "2",
+				SHA:             "2dd3a8d2088457e5cf991018edf13e25cbd61380",
+				Branch:          "patch-2",
+				Labels:          []string{"deploy"},
+				Number:          2,
+				URL:             "https://github.com/fluxcd-testing/pr-testing/pull/2",
+				BaseBranch:      "main",
+				HeadRepository:  "fluxcd-testing/pr-testing",
+				CommitTimestamp: "2025-01-02T10:00:00Z",
+				Draft:           true,
+				UpdatedAt:       "2025-01-02T10:00:00Z",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -175,3 +260,40 @@ func TestOverrideFromExportedInputs(t *testing.T) {
 		})
 	}
 }
+
+func TestSetRepository(t *testing.T) {
+	g := NewWithT(t)
+
+	app := Result{ID: "5", Number: 5}
+	app.SetRepository("org/team/app")
+	g.Expect(app.Repository).To(Equal("app"))
+	g.Expect(app.RepositoryPath).To(Equal("org/team/app"))
+	g.Expect(app.ID).To(Equal(checksum("org/team/app/5")))
+	g.Expect(app.Number).To(Equal(5))
+
+	api := Result{ID: "5", Number: 5}
+	api.SetRepository("org/team/api")
+	g.Expect(api.ID).NotTo(Equal(app.ID))
+}
+
+// stripRequestTimes checks that the base branch and the timestamps of the
+// pull requests returned by the live APIs are set, and clears them to compare
+// the results with the fixtures, as they change when the pull requests are updated.
+func stripRequestTimes(g *WithT, results []Result) []Result {
+	if results == nil {
+		return nil
+	}
+
+	stripped := make([]Result, 0, len(results))
+	for _, r := range results {
+		g.Expect(r.BaseBranch).NotTo(BeEmpty())
+		g.Expect(time.Parse(time.RFC3339, r.CreatedAt)).Error().NotTo(HaveOccurred())
+		g.Expect(time.Parse(time.RFC3339, r.UpdatedAt)).Error().NotTo(HaveOccurred())
+
+		r.BaseBranch = ""
+		r.CreatedAt = ""
+		r.UpdatedAt = ""
+		stripped = append(stripped, r)
+	}
+	return stripped
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	// SortByCreated orders the results by their creation time.
+	SortByCreated = "created"
+	// SortByUpdated orders the results by their last update time,
+	// or by the head commit time when the update time is not set.
+	SortByUpdated = "updated"
+	// SortByName orders the results by the tag, repository path or branch name.
+	SortByName = "name"
+	// SortBySemver orders the results by the semantic version of the tag.
+	SortBySemver = "semver"
+)
+
+// resultLimiter sorts the results and truncates them to the limit filter,
+// recording the number of dropped results. It is embedded by the providers
+// to implement the LimitReporter interface.
+type resultLimiter struct {
+	dropped int
+}
+
+// Dropped returns the number of results dropped by the limit in the last list call.
+func (l *resultLimiter) Dropped() int {
+	return l.dropped
+}
+
+// limit sorts the results and returns the first N results, where N is the
+// limit filter. The results must contain all the pages of the API response.
+func (l *resultLimiter) limit(opts Options, results []Result) []Result {
+	results, l.dropped = LimitResults(opts, results)
+	return results
+}
+
+// LimitResults sorts the results and returns the first N results, where N
+// is the limit filter, and the number of dropped results. It is used to
+// merge the results fetched from multiple repositories.
+func LimitResults(opts Options, results []Result) ([]Result, int) {
+	sortResults(opts, results)
+
+	if opts.Filters.Limit > 0 && len(results) > opts.Filters.Limit {
+		return results[:opts.Filters.Limit], len(results) - opts.Filters.Limit
+	}
+	return results, 0
+}
+
+// sortResults sorts the results in place by the sort filter. The results that
+// don't have a value for the sort attribute are placed last regardless of the
+// direction, and the ties are ordered by id so that the order doesn't depend
+// on the order returned by the API.
+func sortResults(opts Options, results []Result) {
+	if opts.Filters.SortBy == "" {
+		return
+	}
+
+	slices.SortStableFunc(results, func(a, b Result) int {
+		c, ok := compareResults(opts.Filters.SortBy, a, b)
+		if !ok {
+			return c
+		}
+		if opts.Filters.SortDescending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+		return compareIDs(a.ID, b.ID)
+	})
+}
+
+// compareResults compares the sort attribute of the results. It returns
+// false when at least one of the results doesn't have the attribute, in
+// which case the result is ordered after the other one.
+func compareResults(sortBy string, a, b Result) (int, bool) {
+	switch sortBy {
+	case SortBySemver:
+		va, vb := resultVersion(a), resultVersion(b)
+		if va == nil || vb == nil {
+			return compareMissing(va == nil, vb == nil, a, b), false
+		}
+		return va.Compare(vb), true
+	default:
+		ka, kb := resultSortKey(sortBy, a), resultSortKey(sortBy, b)
+		if ka == "" || kb == "" {
+			return compareMissing(ka == "", kb == "", a, b), false
+		}
+		return cmp.Compare(ka, kb), true
+	}
+}
+
+// compareMissing orders the results with a missing attribute last,
+// and the ones with both attributes missing by id.
+func compareMissing(missingA, missingB bool, a, b Result) int {
+	switch {
+	case missingA && missingB:
+		return compareIDs(a.ID, b.ID)
+	case missingA:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// resultSortKey returns the value of the sort attribute of the result.
+// The times are formatted as RFC3339 in UTC, so they compare as strings.
+func resultSortKey(sortBy string, r Result) string {
+	switch sortBy {
+	case SortByCreated:
+		return r.CreatedAt
+	case SortByUpdated:
+		return cmp.Or(r.UpdatedAt, r.CommitTimestamp)
+	case SortByName:
+		return cmp.Or(r.Tag, r.RepositoryPath, r.Branch)
+	default:
+		return ""
+	}
+}
+
+// resultVersion returns the semantic version of the result tag,
+// or nil if the tag is not a valid semantic version.
+func resultVersion(r Result) *semver.Version {
+	v, err := semver.NewVersion(cmp.Or(r.Version, r.Tag))
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// compareIDs compares the ids numerically when both are numbers,
+// e.g. the pull request numbers, and as strings otherwise.
+func compareIDs(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return cmp.Compare(na, nb)
+	}
+	return cmp.Compare(a, b)
+}
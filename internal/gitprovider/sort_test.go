@@ -0,0 +1,100 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestResultLimiter(t *testing.T) {
+	requests := []Result{
+		{ID: "12", Branch: "fix-b", CreatedAt: "2025-01-02T00:00:00Z", UpdatedAt: "2025-01-05T00:00:00Z"},
+		{ID: "3", Branch: "feat-a", CreatedAt: "2025-01-01T00:00:00Z", UpdatedAt: "2025-01-03T00:00:00Z"},
+		{ID: "7", Branch: "docs", UpdatedAt: "2025-01-04T00:00:00Z"},
+		{ID: "20", Branch: "feat-c", CreatedAt: "2025-01-02T00:00:00Z", CommitTimestamp: "2025-01-06T00:00:00Z"},
+		{ID: "15", Branch: "feat-d", CreatedAt: "2025-01-03T00:00:00Z"},
+	}
+	tags := []Result{
+		{ID: "1", Tag: "v1.10.0"},
+		{ID: "2", Tag: "latest"},
+		{ID: "3", Tag: "v1.9.2"},
+		{ID: "4", Tag: "v2.0.0"},
+	}
+
+	tests := []struct {
+		name        string
+		results     []Result
+		filters     Filters
+		want        []string
+		wantDropped int
+	}{
+		{
+			name:        "keeps the API order without sort",
+			results:     requests,
+			filters:     Filters{Limit: 2},
+			want:        []string{"12", "3"},
+			wantDropped: 3,
+		},
+		{
+			name:    "sorts by created with ties ordered by id",
+			results: requests,
+			filters: Filters{SortBy: SortByCreated},
+			want:    []string{"3", "12", "20", "15", "7"},
+		},
+		{
+			name:        "sorts by created in descending order with missing values last",
+			results:     requests,
+			filters:     Filters{SortBy: SortByCreated, SortDescending: true, Limit: 4},
+			want:        []string{"15", "12", "20", "3"},
+			wantDropped: 1,
+		},
+		{
+			name:    "sorts by updated with commit timestamp fallback",
+			results: requests,
+			filters: Filters{SortBy: SortByUpdated, SortDescending: true},
+			want:    []string{"20", "12", "7", "3", "15"},
+		},
+		{
+			name:        "sorts by name",
+			results:     requests,
+			filters:     Filters{SortBy: SortByName, Limit: 3},
+			want:        []string{"7", "3", "20"},
+			wantDropped: 2,
+		},
+		{
+			name:    "sorts by semver with invalid versions last",
+			results: tags,
+			filters: Filters{SortBy: SortBySemver},
+			want:    []string{"3", "1", "4", "2"},
+		},
+		{
+			name:        "sorts by semver in descending order",
+			results:     tags,
+			filters:     Filters{SortBy: SortBySemver, SortDescending: true, Limit: 2},
+			want:        []string{"4", "1"},
+			wantDropped: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			results := make([]Result, len(tt.results))
+			copy(results, tt.results)
+
+			limiter := resultLimiter{dropped: -1}
+			got := limiter.limit(Options{Filters: tt.filters}, results)
+
+			ids := make([]string, 0, len(got))
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+			g.Expect(ids).To(Equal(tt.want))
+			g.Expect(limiter.Dropped()).To(Equal(tt.wantDropped))
+		})
+	}
+}
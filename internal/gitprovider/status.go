@@ -0,0 +1,47 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+// CommitStatusState is the state of a commit status.
+type CommitStatusState string
+
+const (
+	// CommitStatusPending is reported while the changes are being applied.
+	CommitStatusPending CommitStatusState = "pending"
+
+	// CommitStatusSuccess is reported when the changes were applied successfully.
+	CommitStatusSuccess CommitStatusState = "success"
+
+	// CommitStatusFailure is reported when the changes failed to apply.
+	CommitStatusFailure CommitStatusState = "failure"
+)
+
+// maxStatusDescription is the maximum length of the
+// commit status description accepted by GitHub.
+const maxStatusDescription = 140
+
+// CommitStatus holds the status reported for a commit.
+type CommitStatus struct {
+	// Context is the unique name of the status, e.g. 'flux-operator/apps/preview'.
+	Context string
+
+	// State is the state of the status.
+	State CommitStatusState
+
+	// Description is a short description of the status.
+	Description string
+
+	// TargetURL is the optional URL linked to the status.
+	TargetURL string
+}
+
+// description returns the status description
+// truncated to the maximum length allowed.
+func (s CommitStatus) description() string {
+	runes := []rune(s.Description)
+	if len(runes) <= maxStatusDescription {
+		return s.Description
+	}
+	return string(runes[:maxStatusDescription-3]) + "..."
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCommitStatus_Description(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{
+			name:        "short description",
+			description: "Reconciliation succeeded",
+			want:        "Reconciliation succeeded",
+		},
+		{
+			name:        "max length description",
+			description: strings.Repeat("a", maxStatusDescription),
+			want:        strings.Repeat("a", maxStatusDescription),
+		},
+		{
+			name:        "truncated description",
+			description: strings.Repeat("ü", maxStatusDescription+1),
+			want:        strings.Repeat("ü", maxStatusDescription-3) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			status := CommitStatus{Description: tt.description}
+			g.Expect(status.description()).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// filterTags returns the tags that match the regex and semver range filters.
+// Tags that are valid semver versions are sorted in descending order. When
+// keepUnversioned is set, they are followed by the rest of the tags in their
+// original order, unless the semver range or the latest per minor filters are
+// set. For latest per minor, only the latest N versions of each minor release
+// line are kept. The limit filter is applied by the providers after the tags
+// are sorted.
+func filterTags(opts Options, tags []Result, keepUnversioned bool) []Result {
+	type versionedTag struct {
+		version *semver.Version
+		result  Result
+	}
+
+	keepUnversioned = keepUnversioned && opts.Filters.SemverRange == nil && opts.Filters.LatestPerMinor == 0
+
+	var versioned []versionedTag
+	var unversioned []Result
+	for _, tag := range tags {
+		if !matchTag(opts, tag.Tag) {
+			continue
+		}
+
+		v, err := semver.NewVersion(tag.Tag)
+		if err != nil {
+			if keepUnversioned {
+				unversioned = append(unversioned, tag)
+			}
+			continue
+		}
+
+		if opts.Filters.SemverRange != nil && !opts.Filters.SemverRange.Check(v) {
+			continue
+		}
+
+		tag.Version = v.String()
+		versioned = append(versioned, versionedTag{version: v, result: tag})
+	}
+
+	sort.SliceStable(versioned, func(i, j int) bool {
+		return versioned[i].version.GreaterThan(versioned[j].version)
+	})
+
+	var results []Result
+	perMinor := make(map[string]int)
+	for _, m := range versioned {
+		if opts.Filters.LatestPerMinor > 0 {
+			minor := fmt.Sprintf("%d.%d", m.version.Major(), m.version.Minor())
+			if perMinor[minor] >= opts.Filters.LatestPerMinor {
+				continue
+			}
+			perMinor[minor]++
+		}
+
+		results = append(results, m.result)
+	}
+	results = append(results, unversioned...)
+
+	return results
+}
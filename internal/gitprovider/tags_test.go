@@ -0,0 +1,117 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	. "github.com/onsi/gomega"
+)
+
+func TestFilterTags(t *testing.T) {
+	tags := []Result{
+		{ID: "1", SHA: "a1", Tag: "v1.0.0"},
+		{ID: "2", SHA: "a2", Tag: "v1.0.1"},
+		{ID: "3", SHA: "a3", Tag: "v1.1.0"},
+		{ID: "4", SHA: "a4", Tag: "v1.1.1"},
+		{ID: "5", SHA: "a5", Tag: "v1.1.2"},
+		{ID: "6", SHA: "a6", Tag: "v1.2.0-rc.1"},
+		{ID: "7", SHA: "a7", Tag: "v1.2.0"},
+		{ID: "8", SHA: "a8", Tag: "v2.0.0"},
+		{ID: "9", SHA: "a9", Tag: "latest"},
+	}
+
+	tests := []struct {
+		name            string
+		semverRange     string
+		filters         Filters
+		keepUnversioned bool
+		want            []string
+	}{
+		{
+			name: "sorts semver tags",
+			want: []string{"v2.0.0", "v1.2.0", "v1.2.0-rc.1", "v1.1.2", "v1.1.1", "v1.1.0", "v1.0.1", "v1.0.0"},
+		},
+		{
+			name:            "sorts semver tags and keeps unversioned tags",
+			keepUnversioned: true,
+			want:            []string{"v2.0.0", "v1.2.0", "v1.2.0-rc.1", "v1.1.2", "v1.1.1", "v1.1.0", "v1.0.1", "v1.0.0", "latest"},
+		},
+		{
+			name: "filters tags by regex",
+			filters: Filters{
+				IncludeTagRe: regexp.MustCompile(`^v1\.`),
+				ExcludeTagRe: regexp.MustCompile(`-rc\.`),
+			},
+			want: []string{"v1.2.0", "v1.1.2", "v1.1.1", "v1.1.0", "v1.0.1", "v1.0.0"},
+		},
+		{
+			name: "filters non-semver tags by regex",
+			filters: Filters{
+				IncludeTagRe: regexp.MustCompile(`^latest$`),
+			},
+			keepUnversioned: true,
+			want:            []string{"latest"},
+		},
+		{
+			name:            "filters tags by semver range",
+			semverRange:     ">=1.1.0 <2.0.0",
+			keepUnversioned: true,
+			want:            []string{"v1.2.0", "v1.1.2", "v1.1.1", "v1.1.0"},
+		},
+		{
+			name:        "filters tags by latest per minor",
+			semverRange: "1.x",
+			filters: Filters{
+				LatestPerMinor: 1,
+			},
+			want: []string{"v1.2.0", "v1.1.2", "v1.0.1"},
+		},
+		{
+			name:        "filters tags by latest per minor and limit",
+			semverRange: "1.x",
+			filters: Filters{
+				LatestPerMinor: 2,
+				Limit:          3,
+			},
+			want: []string{"v1.2.0", "v1.1.2", "v1.1.1"},
+		},
+		{
+			name:        "sorts tags in ascending order before the limit",
+			semverRange: ">=1.1.0",
+			filters: Filters{
+				SortBy: SortBySemver,
+				Limit:  3,
+			},
+			want: []string{"v1.1.0", "v1.1.1", "v1.1.2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts := Options{Filters: tt.filters}
+			if tt.semverRange != "" {
+				constraint, err := semver.NewConstraint(tt.semverRange)
+				g.Expect(err).NotTo(HaveOccurred())
+				opts.Filters.SemverRange = constraint
+			}
+
+			var limiter resultLimiter
+			got := limiter.limit(opts, filterTags(opts, tags, tt.keepUnversioned))
+
+			gotTags := make([]string, 0, len(got))
+			for _, r := range got {
+				if r.Tag != "latest" {
+					g.Expect(r.Version).To(Equal(r.Tag[1:]))
+				}
+				gotTags = append(gotTags, r.Tag)
+			}
+			g.Expect(gotTags).To(Equal(tt.want))
+		})
+	}
+}
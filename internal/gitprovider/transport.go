@@ -0,0 +1,310 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxCacheSize is the maximum size in bytes of the response bodies
+	// stored in the shared cache used for conditional requests.
+	maxCacheSize = 64 << 20
+
+	// maxCacheEntrySize is the maximum size in bytes of a cached response body.
+	maxCacheEntrySize = 8 << 20
+
+	// defaultRetryAfter is the retry interval used when the rate limited
+	// response doesn't contain the Retry-After or the reset headers.
+	defaultRetryAfter = time.Minute
+)
+
+// RateLimit holds the API rate limit status reported by the Git provider.
+type RateLimit struct {
+	// Limit is the maximum number of requests allowed in the current window.
+	Limit int
+
+	// Remaining is the number of requests remaining in the current window.
+	Remaining int
+
+	// ResetAt is the time when the current rate limit window resets.
+	ResetAt time.Time
+
+	// RetryAt is set when the last request was rejected by the Git provider
+	// due to rate limiting, and holds the time when requests can be resumed.
+	RetryAt time.Time
+}
+
+// Exceeded returns true if the last request was rate limited.
+func (rl *RateLimit) Exceeded() bool {
+	return !rl.RetryAt.IsZero()
+}
+
+// cacheEntry holds the ETag and the body of a cached response.
+type cacheEntry struct {
+	key    string
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// responseCache is an LRU cache of the API responses with an ETag,
+// bounded by the total size of the cached bodies.
+type responseCache struct {
+	mu      sync.Mutex
+	maxSize int
+	size    int
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+func newResponseCache(maxSize int) *responseCache {
+	return &responseCache{
+		maxSize: maxSize,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// sharedResponseCache is used by all the GitHub and GitLab
+// providers to store the responses for conditional requests.
+var sharedResponseCache = newResponseCache(maxCacheSize)
+
+func (c *responseCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*cacheEntry), true
+}
+
+func (c *responseCache) set(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[entry.key]; ok {
+		c.size -= len(el.Value.(*cacheEntry).body)
+		c.ll.Remove(el)
+		delete(c.entries, entry.key)
+	}
+
+	c.entries[entry.key] = c.ll.PushFront(entry)
+	c.size += len(entry.body)
+
+	for c.size > c.maxSize {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		e := oldest.Value.(*cacheEntry)
+		c.size -= len(e.body)
+		c.ll.Remove(oldest)
+		delete(c.entries, e.key)
+	}
+}
+
+// cachingTransport is an http.RoundTripper that uses the ETag of the cached
+// responses to make conditional requests, and records the API rate limit
+// status reported in the response headers.
+type cachingTransport struct {
+	base  http.RoundTripper
+	cache *responseCache
+
+	mu        sync.Mutex
+	rateLimit *RateLimit
+}
+
+func newCachingTransport(base http.RoundTripper) *cachingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &cachingTransport{
+		base:  base,
+		cache: sharedResponseCache,
+	}
+}
+
+// RateLimit returns the rate limit status from the last response,
+// or nil if the API didn't report the rate limit.
+func (t *cachingTransport) RateLimit() *RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.rateLimit == nil {
+		return nil
+	}
+	rl := *t.rateLimit
+	return &rl
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.roundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached, found := t.cache.get(key)
+	if found {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := t.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && found:
+		_ = resp.Body.Close()
+		header := cached.header.Clone()
+		for _, h := range rateLimitHeaders {
+			if v := resp.Header.Get(h); v != "" {
+				header.Set(h, v)
+			}
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(cached.body)),
+			ContentLength: int64(len(cached.body)),
+			Request:       resp.Request,
+		}, nil
+	case resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+		if resp.ContentLength > maxCacheEntrySize {
+			return resp, nil
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxCacheEntrySize+1))
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(body) <= maxCacheEntrySize {
+			t.cache.set(&cacheEntry{
+				key:    key,
+				etag:   resp.Header.Get("ETag"),
+				header: resp.Header.Clone(),
+				body:   body,
+			})
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	default:
+		return resp, nil
+	}
+}
+
+// roundTrip sends the request and records the rate limit status.
+func (t *cachingTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if rl := parseRateLimit(resp, time.Now()); rl != nil {
+		t.mu.Lock()
+		t.rateLimit = rl
+		t.mu.Unlock()
+	}
+
+	return resp, nil
+}
+
+// rateLimitHeaders are the GitHub and GitLab rate limit headers.
+var rateLimitHeaders = []string{
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+}
+
+// isFatalLookupError returns true if an optional lookup request failed because
+// the context is done, or the response is forbidden or rate limited. These
+// errors fail the listing instead of exporting incomplete results, as the
+// following requests would fail too.
+func isFatalLookupError(ctx context.Context, resp *http.Response) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	return resp != nil &&
+		(resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests)
+}
+
+// parseRateLimit returns the rate limit status from the GitHub
+// X-RateLimit-* or the GitLab RateLimit-* response headers.
+// If the response is rate limited, the retry time is computed from
+// the Retry-After header, or from the reset time of the rate limit.
+func parseRateLimit(resp *http.Response, now time.Time) *RateLimit {
+	prefix := "X-RateLimit-"
+	if resp.Header.Get(prefix+"Remaining") == "" {
+		prefix = "RateLimit-"
+	}
+
+	var rl *RateLimit
+	if remaining, err := strconv.Atoi(resp.Header.Get(prefix + "Remaining")); err == nil {
+		rl = &RateLimit{Remaining: remaining}
+		rl.Limit, _ = strconv.Atoi(resp.Header.Get(prefix + "Limit"))
+		if reset, err := strconv.ParseInt(resp.Header.Get(prefix+"Reset"), 10, 64); err == nil {
+			rl.ResetAt = time.Unix(reset, 0)
+		}
+	}
+
+	retryAfter := resp.Header.Get("Retry-After")
+	limited := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && (retryAfter != "" || (rl != nil && rl.Remaining == 0)))
+	if !limited {
+		return rl
+	}
+
+	if rl == nil {
+		rl = &RateLimit{}
+	}
+
+	switch {
+	case retryAfter != "":
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			rl.RetryAt = now.Add(time.Duration(seconds) * time.Second)
+		} else if date, err := http.ParseTime(retryAfter); err == nil {
+			rl.RetryAt = date
+		}
+	case rl.ResetAt.After(now):
+		rl.RetryAt = rl.ResetAt
+	}
+
+	if !rl.RetryAt.After(now) {
+		rl.RetryAt = now.Add(defaultRetryAfter)
+	}
+
+	return rl
+}
+
+// cacheKey returns the cache key of the request computed from the URL
+// and the credentials, to avoid sharing responses between different tokens.
+func cacheKey(req *http.Request) string {
+	h := sha256.New()
+	h.Write([]byte(req.Header.Get("Authorization")))
+	h.Write([]byte(req.Header.Get("PRIVATE-TOKEN")))
+	h.Write([]byte(req.Header.Get("JOB-TOKEN")))
+	return req.URL.String() + "#" + hex.EncodeToString(h.Sum(nil))
+}
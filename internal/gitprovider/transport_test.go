@@ -0,0 +1,195 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package gitprovider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCachingTransport_ConditionalRequests(t *testing.T) {
+	g := NewWithT(t)
+
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Reset", "1735689600")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "4998")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name":"main"}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	transport := newCachingTransport(nil)
+	transport.cache = newResponseCache(1024)
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/branches", nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer token")
+
+		resp, err := client.Do(req)
+		g.Expect(err).NotTo(HaveOccurred())
+		body, err := io.ReadAll(resp.Body)
+		g.Expect(err).NotTo(HaveOccurred())
+		_ = resp.Body.Close()
+
+		g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		g.Expect(string(body)).To(Equal(`[{"name":"main"}]`))
+		g.Expect(resp.Header.Get("Content-Type")).To(Equal("application/json"))
+	}
+
+	g.Expect(requests).To(Equal(3))
+	g.Expect(notModified).To(Equal(2))
+
+	rl := transport.RateLimit()
+	g.Expect(rl).NotTo(BeNil())
+	g.Expect(rl.Limit).To(Equal(5000))
+	g.Expect(rl.Remaining).To(Equal(4999))
+	g.Expect(rl.ResetAt).To(Equal(time.Unix(1735689600, 0)))
+	g.Expect(rl.Exceeded()).To(BeFalse())
+
+	// A request with different credentials must not use the cached response.
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/branches", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	req.Header.Set("Authorization", "Bearer other-token")
+	resp, err := client.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	_ = resp.Body.Close()
+	g.Expect(notModified).To(Equal(2))
+}
+
+func TestResponseCache_Eviction(t *testing.T) {
+	g := NewWithT(t)
+
+	c := newResponseCache(10)
+	c.set(&cacheEntry{key: "a", body: []byte("12345")})
+	c.set(&cacheEntry{key: "b", body: []byte("12345")})
+
+	// Use the first entry to evict the second one.
+	_, found := c.get("a")
+	g.Expect(found).To(BeTrue())
+
+	c.set(&cacheEntry{key: "c", body: []byte("123")})
+	_, found = c.get("b")
+	g.Expect(found).To(BeFalse())
+	_, found = c.get("a")
+	g.Expect(found).To(BeTrue())
+	_, found = c.get("c")
+	g.Expect(found).To(BeTrue())
+	g.Expect(c.size).To(Equal(8))
+}
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Unix(1735689000, 0)
+
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		want   *RateLimit
+	}{
+		{
+			name:   "no rate limit headers",
+			status: http.StatusOK,
+			want:   nil,
+		},
+		{
+			name:   "GitHub quota available",
+			status: http.StatusOK,
+			header: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "10",
+				"X-RateLimit-Reset":     "1735689600",
+			},
+			want: &RateLimit{Limit: 5000, Remaining: 10, ResetAt: time.Unix(1735689600, 0)},
+		},
+		{
+			name:   "GitHub primary rate limit exceeded",
+			status: http.StatusForbidden,
+			header: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     "1735689600",
+			},
+			want: &RateLimit{
+				Limit:     5000,
+				Remaining: 0,
+				ResetAt:   time.Unix(1735689600, 0),
+				RetryAt:   time.Unix(1735689600, 0),
+			},
+		},
+		{
+			name:   "GitHub secondary rate limit with Retry-After",
+			status: http.StatusForbidden,
+			header: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "100",
+				"Retry-After":           "30",
+			},
+			want: &RateLimit{
+				Limit:     5000,
+				Remaining: 100,
+				RetryAt:   now.Add(30 * time.Second),
+			},
+		},
+		{
+			name:   "GitHub forbidden without rate limit",
+			status: http.StatusForbidden,
+			header: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "100",
+			},
+			want: &RateLimit{Limit: 5000, Remaining: 100},
+		},
+		{
+			name:   "GitLab rate limit exceeded",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{
+				"RateLimit-Limit":     "2000",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "1735689060",
+			},
+			want: &RateLimit{
+				Limit:     2000,
+				Remaining: 0,
+				ResetAt:   time.Unix(1735689060, 0),
+				RetryAt:   time.Unix(1735689060, 0),
+			},
+		},
+		{
+			name:   "too many requests without headers",
+			status: http.StatusTooManyRequests,
+			want:   &RateLimit{RetryAt: now.Add(defaultRetryAfter)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+
+			g.Expect(parseRateLimit(resp, now)).To(Equal(tt.want))
+		})
+	}
+}
@@ -28,6 +28,22 @@ func MustRegisterMetrics() {
 		i++
 	}
 	crtlmetrics.Registry.MustRegister(collectors...)
+	crtlmetrics.Registry.MustRegister(rateLimitRemaining)
+}
+
+// RecordRateLimit records the remaining API rate limit quota
+// for the given ResourceSetInputProvider and URL.
+func RecordRateLimit(name, namespace, url string, remaining int) {
+	rateLimitRemaining.WithLabelValues(name, namespace, url).Set(float64(remaining))
+}
+
+// DeleteRateLimits deletes the remaining API rate limit
+// quota of all the URLs of the given ResourceSetInputProvider.
+func DeleteRateLimits(name, namespace string) {
+	rateLimitRemaining.DeletePartialMatch(map[string]string{
+		"name":               name,
+		"exported_namespace": namespace,
+	})
 }
 
 // RecordMetrics records the metrics for the given object.
@@ -95,6 +111,9 @@ func DeleteMetricsFor(kind, name, namespace string) {
 		"name":               name,
 		"exported_namespace": namespace,
 	})
+	if kind == fluxcdv1.ResourceSetInputProviderKind {
+		DeleteRateLimits(name, namespace)
+	}
 }
 
 const (
@@ -141,6 +160,14 @@ var metrics = map[string]*prometheus.GaugeVec{
 	),
 }
 
+var rateLimitRemaining = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "flux_resourcesetinputprovider_ratelimit_remaining",
+		Help: "The remaining API rate limit quota of a Flux Operator ResourceSetInputProvider.",
+	},
+	[]string{"name", "exported_namespace", "url"},
+)
+
 func commonLabelsToValues(obj unstructured.Unstructured) prometheus.Labels {
 	labels := prometheus.Labels{}
 	labels["uid"] = string(obj.GetUID())
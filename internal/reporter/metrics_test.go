@@ -214,3 +214,51 @@ func TestRecordMetrics_ResourceSet(t *testing.T) {
 	g.Expect(metricFamilies).To(HaveLen(1))
 	g.Expect(metricFamilies[0].Metric).To(HaveLen(1))
 }
+
+func TestRecordRateLimit(t *testing.T) {
+	g := NewWithT(t)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(rateLimitRemaining)
+
+	RecordRateLimit("test", "flux-system", "https://github.com/fluxcd/flux2", 4999)
+	RecordRateLimit("test", "flux-system", "https://github.com/fluxcd/flux2", 4998)
+	RecordRateLimit("test2", "flux-system", "https://github.com/fluxcd/flux2", 100)
+
+	metricFamilies, err := reg.Gather()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metricFamilies).To(HaveLen(1))
+	g.Expect(metricFamilies[0].Metric).To(HaveLen(2))
+
+	rlMetric := metricFamilies[0].Metric[0]
+	g.Expect(rlMetric.GetGauge().GetValue()).To(Equal(float64(4998)))
+	rlLabels := rlMetric.GetLabel()
+	g.Expect(rlLabels).To(HaveLen(3))
+	g.Expect(rlLabels[0].GetName()).To(Equal("exported_namespace"))
+	g.Expect(rlLabels[0].GetValue()).To(Equal("flux-system"))
+	g.Expect(rlLabels[1].GetName()).To(Equal("name"))
+	g.Expect(rlLabels[1].GetValue()).To(Equal("test"))
+	g.Expect(rlLabels[2].GetName()).To(Equal("url"))
+	g.Expect(rlLabels[2].GetValue()).To(Equal("https://github.com/fluxcd/flux2"))
+
+	RecordRateLimit("test", "flux-system", "https://github.com/fluxcd/flux", 10)
+	metricFamilies, err = reg.Gather()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metricFamilies[0].Metric).To(HaveLen(3))
+
+	DeleteRateLimits("test", "flux-system")
+	RecordRateLimit("test", "flux-system", "https://github.com/fluxcd/flux", 9)
+	metricFamilies, err = reg.Gather()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metricFamilies[0].Metric).To(HaveLen(2))
+	for _, m := range metricFamilies[0].Metric {
+		if m.GetLabel()[1].GetValue() == "test" {
+			g.Expect(m.GetLabel()[2].GetValue()).To(Equal("https://github.com/fluxcd/flux"))
+			g.Expect(m.GetGauge().GetValue()).To(Equal(float64(9)))
+		}
+	}
+
+	DeleteMetricsFor(fluxcdv1.ResourceSetInputProviderKind, "test", "flux-system")
+	metricFamilies, err = reg.Gather()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metricFamilies[0].Metric).To(HaveLen(1))
+}
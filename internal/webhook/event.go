@@ -0,0 +1,148 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	providerGitHub  = "GitHub"
+	providerGitLab  = "GitLab"
+	providerUnknown = "unknown"
+)
+
+// githubEvents are the GitHub event types that trigger a reconciliation.
+var githubEvents = []string{"push", "pull_request", "create", "delete"}
+
+// gitlabEvents are the GitLab event types that trigger a reconciliation.
+var gitlabEvents = []string{"Push Hook", "Tag Push Hook", "Merge Request Hook"}
+
+// Event holds the details of a validated webhook request.
+type Event struct {
+	// Provider is the Git provider that sent the event,
+	// matching the prefix of the ResourceSetInputProvider type.
+	Provider string
+
+	// Type is the event type sent by the Git provider.
+	Type string
+
+	// RepositoryURLs are the web and clone URLs of the repository.
+	RepositoryURLs []string
+
+	// Ignored is set for the event types that don't trigger a reconciliation.
+	Ignored bool
+}
+
+// parseEvent detects the Git provider from the request headers, validates
+// the request signature or token with the secret, and extracts the
+// repository URLs from the payload.
+func parseEvent(header http.Header, body []byte, secret []byte) (Event, error) {
+	switch {
+	case header.Get("X-GitHub-Event") != "":
+		return parseGitHubEvent(header, body, secret)
+	case header.Get("X-Gitlab-Event") != "":
+		return parseGitLabEvent(header, body, secret)
+	default:
+		return Event{Provider: providerUnknown}, errors.New("unknown webhook provider")
+	}
+}
+
+func parseGitHubEvent(header http.Header, body []byte, secret []byte) (Event, error) {
+	event := Event{
+		Provider: providerGitHub,
+		Type:     header.Get("X-GitHub-Event"),
+	}
+
+	signature := header.Get("X-Hub-Signature-256")
+	if signature == "" {
+		return event, errors.New("missing X-Hub-Signature-256 header")
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return event, errors.New("invalid X-Hub-Signature-256 signature")
+	}
+
+	if !slices.Contains(githubEvents, event.Type) {
+		event.Ignored = true
+		return event, nil
+	}
+
+	var payload struct {
+		Repository struct {
+			HTMLURL  string `json:"html_url"`
+			CloneURL string `json:"clone_url"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return event, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	event.RepositoryURLs = nonEmpty(payload.Repository.HTMLURL, payload.Repository.CloneURL)
+	if len(event.RepositoryURLs) == 0 {
+		return event, errors.New("invalid payload: repository URL not found")
+	}
+
+	return event, nil
+}
+
+func parseGitLabEvent(header http.Header, body []byte, secret []byte) (Event, error) {
+	event := Event{
+		Provider: providerGitLab,
+		Type:     header.Get("X-Gitlab-Event"),
+	}
+
+	token := header.Get("X-Gitlab-Token")
+	if token == "" {
+		return event, errors.New("missing X-Gitlab-Token header")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), secret) != 1 {
+		return event, errors.New("invalid X-Gitlab-Token token")
+	}
+
+	if !slices.Contains(gitlabEvents, event.Type) {
+		event.Ignored = true
+		return event, nil
+	}
+
+	var payload struct {
+		Project struct {
+			WebURL     string `json:"web_url"`
+			GitHTTPURL string `json:"git_http_url"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return event, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	event.RepositoryURLs = nonEmpty(payload.Project.WebURL, payload.Project.GitHTTPURL)
+	if len(event.RepositoryURLs) == 0 {
+		return event, errors.New("invalid payload: project URL not found")
+	}
+
+	return event, nil
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
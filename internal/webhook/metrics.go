@@ -0,0 +1,34 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package webhook
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	crtlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	resultAccepted = "accepted"
+	resultIgnored  = "ignored"
+	resultRejected = "rejected"
+	resultFailed   = "failed"
+)
+
+var requestsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "flux_webhook_receiver_requests_total",
+		Help: "The total number of webhook requests handled by the Flux Operator receiver.",
+	},
+	[]string{"provider", "result"},
+)
+
+// MustRegisterMetrics attempts to register the webhook receiver
+// metrics collectors in the controller-runtime metrics registry.
+func MustRegisterMetrics() {
+	crtlmetrics.Registry.MustRegister(requestsTotal)
+}
+
+func recordRequest(provider, result string) {
+	requestsTotal.WithLabelValues(provider, result).Inc()
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+const (
+	// ReceiverPath is the HTTP path of the webhook receiver.
+	ReceiverPath = "/hook/resourcesetinputprovider"
+
+	// maxPayloadSize is the maximum size of the webhook payload,
+	// matching the maximum payload size sent by GitHub.
+	maxPayloadSize = 25 << 20
+)
+
+// Receiver is an HTTP server that handles the GitHub and GitLab webhook
+// events, and requests the reconciliation of the ResourceSetInputProviders
+// with the spec.url matching the repository of the event.
+type Receiver struct {
+	// Client is used to list and annotate the ResourceSetInputProviders.
+	Client client.Client
+
+	// Address is the address the HTTP server binds to.
+	Address string
+
+	// Secret is used to validate the GitHub HMAC signature
+	// and the GitLab secret token of the webhook requests.
+	Secret []byte
+}
+
+// NeedLeaderElection returns false, allowing the receiver
+// to serve requests on all the operator replicas.
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+// Start runs the HTTP server until the context is canceled.
+func (r *Receiver) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("webhook-receiver")
+
+	if len(r.Secret) == 0 {
+		return errors.New("webhook receiver secret is required")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(ReceiverPath, r)
+
+	srv := &http.Server{
+		Addr:              r.Address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		log.Info("starting webhook receiver", "address", r.Address, "path", ReceiverPath)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errChan <- err
+		}
+		close(errChan)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// ServeHTTP validates the webhook request and annotates the
+// ResourceSetInputProviders matching the repository of the event.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	log := ctrl.LoggerFrom(ctx).WithName("webhook-receiver")
+
+	if req.Method != http.MethodPost {
+		recordRequest(providerUnknown, resultRejected)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxPayloadSize))
+	if err != nil {
+		recordRequest(providerUnknown, resultRejected)
+		http.Error(w, "failed to read the request body", http.StatusBadRequest)
+		return
+	}
+
+	event, err := parseEvent(req.Header, body, r.Secret)
+	if err != nil {
+		recordRequest(event.Provider, resultRejected)
+		log.Info("webhook request rejected", "provider", event.Provider, "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if event.Ignored {
+		recordRequest(event.Provider, resultIgnored)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	names, err := r.requestReconciliation(ctx, event)
+	if err != nil {
+		recordRequest(event.Provider, resultFailed)
+		log.Error(err, "failed to request reconciliation", "repository", event.RepositoryURLs)
+		http.Error(w, "failed to request reconciliation", http.StatusInternalServerError)
+		return
+	}
+
+	recordRequest(event.Provider, resultAccepted)
+	log.Info("webhook request accepted",
+		"provider", event.Provider,
+		"event", event.Type,
+		"repository", event.RepositoryURLs[0],
+		"matches", names)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// requestReconciliation sets the reconcile request annotation on the
+// ResourceSetInputProviders of the event provider type that have the
+// spec.url, or one of the spec.urls, matching one of the repository URLs of the event.
+func (r *Receiver) requestReconciliation(ctx context.Context, event Event) ([]string, error) {
+	var list fluxcdv1.ResourceSetInputProviderList
+	if err := r.Client.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", fluxcdv1.ResourceSetInputProviderKind, err)
+	}
+
+	repoURLs := make(map[string]bool, len(event.RepositoryURLs))
+	for _, u := range event.RepositoryURLs {
+		repoURLs[normalizeURL(u)] = true
+	}
+
+	requestedAt := time.Now().Format(time.RFC3339Nano)
+	var names []string
+	for _, obj := range list.Items {
+		if !strings.HasPrefix(obj.Spec.Type, event.Provider) {
+			continue
+		}
+
+		if !slices.ContainsFunc(obj.GetURLs(), func(u string) bool {
+			return repoURLs[normalizeURL(u)]
+		}) {
+			continue
+		}
+
+		patch := client.MergeFrom(obj.DeepCopy())
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[meta.ReconcileRequestAnnotation] = requestedAt
+		obj.SetAnnotations(annotations)
+		if err := r.Client.Patch(ctx, &obj, patch); err != nil {
+			return names, fmt.Errorf("failed to annotate %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+		}
+
+		names = append(names, fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName()))
+	}
+
+	return names, nil
+}
+
+// normalizeURL returns the repository URL without the scheme,
+// the user info, the trailing slash and the .git suffix,
+// with the host in lowercase.
+func normalizeURL(repoURL string) string {
+	u, err := url.Parse(strings.TrimSpace(repoURL))
+	if err != nil || u.Host == "" {
+		return repoURL
+	}
+
+	path := strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+	return strings.ToLower(u.Host) + path
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 Stefan Prodan.
+// SPDX-License-Identifier: AGPL-3.0
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fluxcdv1 "github.com/controlplaneio-fluxcd/flux-operator/api/v1"
+)
+
+func TestReceiver_ServeHTTP(t *testing.T) {
+	secret := []byte("test-secret")
+	githubPayload := []byte(`{"repository":{"html_url":"https://github.com/fluxcd-testing/pr-testing","clone_url":"https://github.com/fluxcd-testing/pr-testing.git"}}`)
+	gitlabPayload := []byte(`{"project":{"web_url":"https://gitlab.com/stefanprodan/podinfo","git_http_url":"https://gitlab.com/stefanprodan/podinfo.git"}}`)
+
+	tests := []struct {
+		name       string
+		header     map[string]string
+		body       []byte
+		wantStatus int
+		wantResult string
+		wantMatch  []string
+	}{
+		{
+			name: "GitHub pull request event",
+			header: map[string]string{
+				"X-GitHub-Event":      "pull_request",
+				"X-Hub-Signature-256": githubSignature(secret, githubPayload),
+			},
+			body:       githubPayload,
+			wantStatus: http.StatusAccepted,
+			wantResult: resultAccepted,
+			wantMatch:  []string{"github-prs", "github-branches", "github-multi-repo"},
+		},
+		{
+			name: "GitHub ping event",
+			header: map[string]string{
+				"X-GitHub-Event":      "ping",
+				"X-Hub-Signature-256": githubSignature(secret, githubPayload),
+			},
+			body:       githubPayload,
+			wantStatus: http.StatusOK,
+			wantResult: resultIgnored,
+		},
+		{
+			name: "GitHub invalid signature",
+			header: map[string]string{
+				"X-GitHub-Event":      "push",
+				"X-Hub-Signature-256": githubSignature([]byte("wrong-secret"), githubPayload),
+			},
+			body:       githubPayload,
+			wantStatus: http.StatusUnauthorized,
+			wantResult: resultRejected,
+		},
+		{
+			name: "GitHub unsigned payload",
+			header: map[string]string{
+				"X-GitHub-Event": "push",
+			},
+			body:       githubPayload,
+			wantStatus: http.StatusUnauthorized,
+			wantResult: resultRejected,
+		},
+		{
+			name: "GitLab merge request event",
+			header: map[string]string{
+				"X-Gitlab-Event": "Merge Request Hook",
+				"X-Gitlab-Token": string(secret),
+			},
+			body:       gitlabPayload,
+			wantStatus: http.StatusAccepted,
+			wantResult: resultAccepted,
+			wantMatch:  []string{"gitlab-mrs"},
+		},
+		{
+			name: "GitLab invalid token",
+			header: map[string]string{
+				"X-Gitlab-Event": "Push Hook",
+				"X-Gitlab-Token": "wrong-secret",
+			},
+			body:       gitlabPayload,
+			wantStatus: http.StatusUnauthorized,
+			wantResult: resultRejected,
+		},
+		{
+			name:       "unknown provider",
+			body:       githubPayload,
+			wantStatus: http.StatusUnauthorized,
+			wantResult: resultRejected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kubeClient := fake.NewClientBuilder().
+				WithScheme(newTestScheme()).
+				WithObjects(
+					newProvider("github-prs", fluxcdv1.InputProviderGitHubPullRequest,
+						"https://github.com/fluxcd-testing/pr-testing"),
+					newProvider("github-branches", fluxcdv1.InputProviderGitHubBranch,
+						"https://GitHub.com/fluxcd-testing/pr-testing.git/"),
+					newProvider("github-other", fluxcdv1.InputProviderGitHubBranch,
+						"https://github.com/fluxcd-testing/other"),
+					newProvider("gitlab-mrs", fluxcdv1.InputProviderGitLabMergeRequest,
+						"https://gitlab.com/stefanprodan/podinfo"),
+					newProvider("gitlab-same-url", fluxcdv1.InputProviderGitLabBranch,
+						"https://github.com/fluxcd-testing/pr-testing"),
+					newProvider("github-multi-repo", fluxcdv1.InputProviderGitHubPullRequest, "",
+						"https://github.com/fluxcd-testing/other",
+						"https://github.com/fluxcd-testing/pr-testing"),
+				).
+				Build()
+
+			receiver := &Receiver{
+				Client: kubeClient,
+				Secret: secret,
+			}
+
+			provider := providerUnknown
+			if tt.header["X-GitHub-Event"] != "" {
+				provider = providerGitHub
+			} else if tt.header["X-Gitlab-Event"] != "" {
+				provider = providerGitLab
+			}
+			counter := requestsTotal.WithLabelValues(provider, tt.wantResult)
+			before := testutil.ToFloat64(counter)
+
+			req := httptest.NewRequest(http.MethodPost, ReceiverPath, bytes.NewReader(tt.body))
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			receiver.ServeHTTP(rec, req)
+
+			g.Expect(rec.Code).To(Equal(tt.wantStatus))
+			g.Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+
+			var list fluxcdv1.ResourceSetInputProviderList
+			g.Expect(kubeClient.List(context.Background(), &list)).To(Succeed())
+
+			var matched []string
+			for _, obj := range list.Items {
+				if _, ok := obj.GetAnnotations()[meta.ReconcileRequestAnnotation]; ok {
+					matched = append(matched, obj.GetName())
+				}
+			}
+			g.Expect(matched).To(ConsistOf(tt.wantMatch))
+		})
+	}
+}
+
+func TestReceiver_ServeHTTP_MethodNotAllowed(t *testing.T) {
+	g := NewWithT(t)
+
+	receiver := &Receiver{
+		Client: fake.NewClientBuilder().WithScheme(newTestScheme()).Build(),
+		Secret: []byte("test-secret"),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, ReceiverPath, nil)
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, req)
+
+	g.Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://github.com/fluxcd/flux2", want: "github.com/fluxcd/flux2"},
+		{url: "https://github.com/fluxcd/flux2/", want: "github.com/fluxcd/flux2"},
+		{url: "https://github.com/fluxcd/flux2.git", want: "github.com/fluxcd/flux2"},
+		{url: "http://GitLab.Example.com/group/sub/project.git", want: "gitlab.example.com/group/sub/project"},
+		{url: "https://user@gitlab.example.com/group/project", want: "gitlab.example.com/group/project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(normalizeURL(tt.url)).To(Equal(tt.want))
+		})
+	}
+}
+
+func githubSignature(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newProvider(name, providerType, url string, urls ...string) client.Object {
+	return &fluxcdv1.ResourceSetInputProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: fluxcdv1.ResourceSetInputProviderSpec{
+			Type: providerType,
+			URL:  url,
+			URLs: urls,
+		},
+	}
+}
+
+func newTestScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	_ = fluxcdv1.AddToScheme(s)
+	return s
+}